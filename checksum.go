@@ -2,6 +2,7 @@ package kidwords
 
 import (
 	"bytes"
+	"errors"
 	"hash"
 	"hash/crc32"
 	"io"
@@ -69,3 +70,54 @@ func ChecksumChop(b []byte) (remainder []byte, ok bool) {
 	}
 	return b, false
 }
+
+// checksumReader reverses [ChecksumWriter]. It passes through everything except the trailing checksum, which it holds back while reading in blocks, and returns an error at the end of the stream if the checksum does not match.
+type checksumReader struct {
+	r          io.Reader
+	hash       hash.Hash32
+	buf        []byte
+	start, end int
+	eof        bool
+	err        error
+}
+
+func newChecksumReader(r io.Reader) *checksumReader {
+	return &checksumReader{
+		r:    r,
+		hash: crc32.New(ChecksumTable),
+		buf:  make([]byte, 512+crc32.Size),
+	}
+}
+
+func (c *checksumReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		if available := c.end - c.start - crc32.Size; available > 0 {
+			n = copy(p, c.buf[c.start:c.start+available])
+			_, _ = c.hash.Write(p[:n])
+			c.start += n
+			return n, nil
+		}
+		if c.err != nil {
+			return 0, c.err
+		}
+		if c.eof {
+			c.err = io.EOF
+			if !bytes.Equal(c.buf[c.start:c.end], c.hash.Sum(nil)) {
+				c.err = errors.New("checksum does not match")
+			}
+			return 0, c.err
+		}
+		c.end = copy(c.buf, c.buf[c.start:c.end])
+		c.start = 0
+		n, err = c.r.Read(c.buf[c.end:])
+		c.end += n
+		if err == io.EOF {
+			c.eof = true
+		} else if err != nil {
+			c.err = err
+		}
+	}
+}
@@ -2,15 +2,17 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 
 	"io"
 	"os"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/dkotik/kidwords"
 	"github.com/dkotik/kidwords/dictionary"
-	"github.com/dkotik/kidwords/shamir"
 	"github.com/urfave/cli/v2"
 )
 
@@ -18,55 +20,131 @@ var combine = &cli.Command{
 	Name:      "combine",
 	Usage:     "recover the secret from a quorum of Shamir's Secret Sharing shards",
 	ArgsUsage: "\"-\" argument takes standard input",
+	Flags: []cli.Flag{
+		passphraseFlag,
+		&cli.BoolFlag{
+			Name:  "legacy",
+			Usage: "combine shards printed by versions that did not record options in the shards",
+		},
+	},
 	Action: func(c *cli.Context) (err error) {
-		input := strings.Join(c.Args().Slice(), " ")
-		if input == "-" {
-			b := &bytes.Buffer{}
-			if _, err = io.Copy(b, os.Stdin); err != nil {
-				return err
-			}
-			lines := bytes.Split(b.Bytes(), []byte("\n"))
-			shards := make([][]byte, 0, len(lines))
-			for _, line := range lines {
-				b.Reset()
-				r, err := kidwords.NewReader(bytes.NewReader(line))
-				if err != nil {
-					return err
-				}
-				if _, err = io.Copy(os.Stdout, r); err != nil {
-					return err
-				}
-				if b.Len() > 0 {
-					clone := make([]byte, b.Len())
-					copy(clone, b.Bytes())
-					shards = append(shards, clone)
-				}
-			}
-			key, err := shamir.Combine(shards)
-			if err != nil {
-				return err
-			}
-			_, err = fmt.Printf("%s", key)
+		options, release, err := newCombineOptions(c)
+		if err != nil {
+			return err
+		}
+		defer release()
+		var (
+			shards   [][]byte
+			erasures [][]int
+		)
+		if strings.Join(c.Args().Slice(), " ") == "-" {
+			shards, erasures, err = readShards(os.Stdin)
+		} else {
+			shards, erasures, err = scanShards()
+		}
+		for _, shard := range shards {
+			defer protect(shard)()
+		}
+		if err != nil {
+			return err
+		}
+		ctx, stop := interruptible(c)
+		defer stop()
+		key, err := kidwords.CombineErasuresContext(ctx, shards, erasures, options...)
+		if err != nil {
 			return err
 		}
+		defer protect(key)()
+		_, err = os.Stdout.Write(key)
+		return err
+	},
+}
 
-		var shards [][]byte
-		for {
-			shard, more, err := scanShard(fmt.Sprintf("Collected %d shards", len(shards)))
-			if err != nil {
-				return err
+func newCombineOptions(c *cli.Context) (options []kidwords.CombineOption, release func(), err error) {
+	release = func() {}
+	percent := uint64(0)
+	options = append(options, kidwords.WithTimeLockProgress(func(done, total uint64) {
+		if p := done * 100 / total; p != percent || done == 1 {
+			percent = p
+			fmt.Fprintf(os.Stderr, "\r ⏳ Solving time-lock: %d%%", p)
+		}
+		if done == total {
+			fmt.Fprintln(os.Stderr)
+		}
+	}))
+	if c.Bool("passphrase") {
+		passphrase, releasePassphrase, err := readPassphrase("Passphrase", passphraseEnvironmentVariable, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		release = releasePassphrase
+		options = append(options, kidwords.WithPassphrase(passphrase))
+	}
+	if c.Bool("legacy") {
+		options = append(options, kidwords.WithLegacyShards())
+	}
+	return options, release, nil
+}
+
+// decodeShard decodes the words of a shard, which may contain placeholders, like "?", for words that cannot be read.
+func decodeShard(words []byte, number int) (shard []byte, erasures []int, err error) {
+	r, err := kidwords.NewReader(bytes.NewReader(words), kidwords.WithErasures())
+	if err != nil {
+		return nil, nil, err
+	}
+	if shard, err = io.ReadAll(r); err != nil {
+		return nil, nil, err
+	}
+	erasures = r.Erasures()
+	if len(shard) > 0 {
+		if len(erasures) > 0 && erasures[len(erasures)-1] == len(shard)-1 {
+			return nil, nil, errors.New("the last word of a shard cannot be recovered when it is erased")
+		}
+		if err = validateShardNumber(shard, number); err != nil {
+			return nil, nil, err
+		}
+	}
+	return shard, erasures, nil
+}
+
+// readShards decodes one shard per line.
+func readShards(in io.Reader) (shards [][]byte, erasures [][]int, err error) {
+	b := &bytes.Buffer{}
+	if _, err = io.Copy(b, in); err != nil {
+		return nil, nil, err
+	}
+	for i, line := range bytes.Split(b.Bytes(), []byte("\n")) {
+		number, words := cutShardNumber(line)
+		shard, erased, err := decodeShard(words, number)
+		if err != nil {
+			var wordErr *kidwords.WordError
+			if errors.As(err, &wordErr) { // position within the input
+				wordErr.Line = i + 1
+				wordErr.Column += utf8.RuneCount(line[:len(line)-len(words)])
 			}
+			return nil, nil, err
+		}
+		if len(shard) > 0 {
 			shards = append(shards, shard)
-			if !more {
-				key, err := shamir.Combine(shards)
-				if err != nil {
-					return err
-				}
-				_, err = fmt.Printf("%s", key)
-				return err
-			}
+			erasures = append(erasures, erased)
 		}
-	},
+	}
+	return shards, erasures, nil
+}
+
+// scanShards prompts for shards one word at a time.
+func scanShards() (shards [][]byte, erasures [][]int, err error) {
+	for {
+		shard, erased, more, err := scanShard(fmt.Sprintf("Collected %d shards", len(shards)))
+		if err != nil {
+			return nil, nil, err
+		}
+		shards = append(shards, shard)
+		erasures = append(erasures, erased)
+		if !more {
+			return shards, erasures, nil
+		}
+	}
 }
 
 func scanWord(prompt string) (string, error) {
@@ -77,14 +155,52 @@ func scanWord(prompt string) (string, error) {
 	return string(bytes.TrimSpace(word)), nil
 }
 
-func scanShard(prompt string) (shard []byte, more bool, err error) {
-	var words []string
+// cutShardNumber removes the shard number printed by split command with "--numbered" flag from the beginning of a line. Returns zero if the line is not numbered.
+func cutShardNumber(line []byte) (int, []byte) {
+	trimmed := bytes.TrimLeft(bytes.TrimSpace(line), "#")
+	end := 0
+	for end < len(trimmed) && trimmed[end] >= '0' && trimmed[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, line
+	}
+	number, err := strconv.Atoi(string(trimmed[:end]))
+	if err != nil {
+		return 0, line
+	}
+	return number, trimmed[end:]
+}
+
+func validateShardNumber(shard []byte, number int) error {
+	if number == 0 {
+		return nil // shard number was not provided
+	}
+	encoded, err := kidwords.ShardNumber(shard)
+	if err != nil {
+		return err
+	}
+	if encoded != number {
+		return fmt.Errorf("shard #%d does not match its words, which belong to shard #%d", number, encoded)
+	}
+	return nil
+}
+
+func scanShard(prompt string) (shard []byte, erasures []int, more bool, err error) {
+	var (
+		words  []string
+		number int
+	)
 
 top:
 	for {
 		word, err := scanWord(fmt.Sprintf("%s, %d words:", prompt, len(words)))
 		if err != nil {
-			return nil, false, err
+			return nil, nil, false, err
+		}
+		if word != "" && strings.Trim(word, "?_") == "" {
+			words = append(words, word) // placeholder for a word that cannot be read
+			continue top
 		}
 		for _, existing := range dictionary.EnglishFourLetterNouns {
 			if existing == word {
@@ -92,23 +208,24 @@ top:
 				continue top
 			}
 		}
+		if n, rest := cutShardNumber([]byte(word)); n > 0 && len(rest) == 0 {
+			number = n
+			fmt.Printf(" ✓ shard #%d\n", number)
+			continue
+		}
 		switch word {
 		case "":
 			// fmt.Println(" ⚠ cannot use an empty word")
+			fmt.Println(" ⚠ submit the shard number, like \"#3\", to check it")
+			fmt.Println(" ⚠ submit \"?\" in place of a word that cannot be read")
 			fmt.Println(" ⚠ submit \"next\" to end the shard")
 			fmt.Println(" ⚠ submit \"done\" to attempt recovery")
-		case "next":
-			shard, err := kidwords.ToBytes(strings.Join(words, " "))
-			if err != nil {
-				return nil, false, err
-			}
-			return shard, true, nil
-		case "done":
-			shard, err := kidwords.ToBytes(strings.Join(words, " "))
+		case "next", "done":
+			shard, erasures, err := decodeShard([]byte(strings.Join(words, " ")), number)
 			if err != nil {
-				return nil, false, err
+				return nil, nil, false, err
 			}
-			return shard, false, nil
+			return shard, erasures, word == "next", nil
 		default:
 			fmt.Printf("word %q is not in the encoding dictionary\n", word)
 		}
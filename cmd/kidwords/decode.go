@@ -1,9 +1,13 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/dkotik/kidwords"
 	"github.com/urfave/cli/v2"
@@ -13,21 +17,52 @@ var decode = &cli.Command{
 	Name:      "decode",
 	Usage:     "convert simple words into data",
 	ArgsUsage: "\"-\" argument takes standard input",
+	Flags:     []cli.Flag{framedFlag, groupFlag, lineFlag, checksumFlag},
 	Action: func(c *cli.Context) error {
-		input := strings.Join(c.Args().Slice(), " ")
-		if input == "-" {
-			r, err := kidwords.NewReader(os.Stdin)
-			if err != nil {
+		var options []kidwords.ReaderOption
+		if layout, ok := newLayout(c); ok {
+			options = append(options, kidwords.WithLayout(layout))
+		}
+		input := []byte(strings.Join(c.Args().Slice(), " "))
+		if string(input) == "-" {
+			var err error
+			if input, err = io.ReadAll(os.Stdin); err != nil {
 				return err
 			}
-			_, err = io.Copy(os.Stdout, r)
+		}
+		if c.Bool("framed") {
+			container, err := kidwords.ReadContainer(bytes.NewReader(input), options...)
+			if err != nil {
+				return highlightWordError(input, err)
+			}
+			fmt.Fprintf(os.Stderr, " 📦 %s\n", container.Kind)
+			_, err = os.Stdout.Write(container.Payload)
 			return err
 		}
-		r, err := kidwords.NewReader(strings.NewReader(input))
+		r, err := kidwords.NewReader(bytes.NewReader(input), options...)
 		if err != nil {
 			return err
 		}
 		_, err = io.Copy(os.Stdout, r)
-		return err
+		return highlightWordError(input, err)
 	},
 }
+
+// highlightWordError prints the input line that contains the unknown word to standard error and marks the word.
+func highlightWordError(input []byte, err error) error {
+	var wordErr *kidwords.WordError
+	if !errors.As(err, &wordErr) {
+		return err
+	}
+	lines := bytes.Split(input, []byte("\n"))
+	if wordErr.Line > len(lines) {
+		return err
+	}
+	line := bytes.TrimRight(lines[wordErr.Line-1], "\r")
+	fmt.Fprintf(os.Stderr, "%s\n%s%s\n",
+		line,
+		strings.Repeat(" ", wordErr.Column-1),
+		strings.Repeat("^", utf8.RuneCountInString(wordErr.Word)),
+	)
+	return err
+}
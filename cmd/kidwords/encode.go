@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -10,31 +11,135 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+var framedFlag = &cli.BoolFlag{
+	Name:    "framed",
+	Aliases: []string{"f"},
+	Usage:   "wrap the data into a container with length and checksum, so that decoding detects mistakes",
+}
+
+var (
+	groupFlag = &cli.IntFlag{
+		Name:    "group",
+		Aliases: []string{"g"},
+		Usage:   "separate every `N` words by two spaces",
+	}
+	lineFlag = &cli.IntFlag{
+		Name:    "line",
+		Aliases: []string{"l"},
+		Usage:   "put `N` groups on each line, requires --group",
+	}
+	checksumFlag = &cli.BoolFlag{
+		Name:    "checksum",
+		Aliases: []string{"c"},
+		Usage:   "end each line with a check word, so that mistakes are found line by line",
+	}
+)
+
+// newLayout returns the layout set by the flags and false when no layout flag is set.
+func newLayout(c *cli.Context) (kidwords.Layout, bool) {
+	layout := kidwords.Layout{
+		GroupSize:     c.Int("group"),
+		GroupsPerLine: c.Int("line"),
+		Numbered:      c.Bool("numbered"),
+		Checksum:      c.Bool("checksum"),
+	}
+	return layout, layout != kidwords.Layout{}
+}
+
 var encode = &cli.Command{
 	Name:      "encode",
 	Usage:     "convert input into simple words",
 	ArgsUsage: "\"-\" argument takes standard input",
-	Action: func(c *cli.Context) error {
-		w, err := kidwords.NewWriter(os.Stdout)
-		if err != nil {
-			return err
+	Flags: []cli.Flag{
+		framedFlag,
+		&cli.BoolFlag{
+			Name:    "compress",
+			Aliases: []string{"z"},
+			Usage:   "compress long text to produce fewer words, implies --framed",
+		},
+		&cli.StringFlag{
+			Name:    "kind",
+			Aliases: []string{"k"},
+			Usage:   "record what the words hold: data, key, shard, or checksummed, implies --framed",
+		},
+		groupFlag,
+		lineFlag,
+		&cli.BoolFlag{
+			Name:    "numbered",
+			Aliases: []string{"n"},
+			Usage:   "number the lines",
+		},
+		checksumFlag,
+	},
+	Action: func(c *cli.Context) (err error) {
+		var (
+			w       io.WriteCloser
+			options []kidwords.WriterOption
+		)
+		if layout, ok := newLayout(c); ok {
+			options = append(options, kidwords.WithLayout(layout))
+		}
+		if c.IsSet("kind") {
+			kind, err := payloadKind(c.String("kind"))
+			if err != nil {
+				return err
+			}
+			options = append(options, kidwords.WithPayloadKind(kind))
+		}
+		if c.Bool("compress") {
+			if w, err = kidwords.NewContainerWriter(os.Stdout, append(options, kidwords.WithCompression())...); err != nil {
+				return err
+			}
+		} else if c.Bool("framed") || c.IsSet("kind") {
+			if w, err = kidwords.NewContainerWriter(os.Stdout, options...); err != nil {
+				return err
+			}
+		} else {
+			if w, err = kidwords.NewWriter(os.Stdout, options...); err != nil {
+				return err
+			}
 		}
 		if strings.Join(c.Args().Slice(), " ") == "-" {
-			_, err = io.Copy(w, os.Stdin)
-			return err
+			if _, err = io.Copy(w, os.Stdin); err != nil {
+				return err
+			}
+			return w.Close()
 		}
 
 		secret, err := scanPassword("Enter secret: ")
 		if err != nil {
 			return err
 		}
-		if _, err = os.Stdout.Write([]byte("Encoded:")); err != nil {
+		defer protect(secret)()
+		prompt := "Encoded:"
+		if options != nil {
+			prompt += "\n"
+		}
+		if _, err = os.Stdout.Write([]byte(prompt)); err != nil {
 			return err
 		}
 		if _, err = io.Copy(w, bytes.NewReader(secret)); err != nil {
 			return err
 		}
+		if err = w.Close(); err != nil {
+			return err
+		}
 		_, err = os.Stdout.Write([]byte("\n"))
 		return err
 	},
 }
+
+func payloadKind(name string) (kidwords.PayloadKind, error) {
+	switch name {
+	case "data":
+		return kidwords.PayloadData, nil
+	case "key":
+		return kidwords.PayloadKey, nil
+	case "shard":
+		return kidwords.PayloadShard, nil
+	case "checksummed":
+		return kidwords.PayloadChecksummed, nil
+	default:
+		return 0, fmt.Errorf("Flag kind value %q is not one of data, key, shard, or checksummed", name)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dkotik/kidwords"
+	"github.com/urfave/cli/v2"
+)
+
+const encryptedFileExtension = ".kidwords"
+
+var splitFile = &cli.Command{
+	Name:      "split-file",
+	Usage:     "encrypt a file and split its key into Shamir's Secret Sharing shards",
+	ArgsUsage: "path to the file",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "encrypted file path, defaults to the file path with \"" + encryptedFileExtension + "\" extension",
+		},
+	}, splitFlags...),
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 1 {
+			return errors.New("provide exactly one file path")
+		}
+		source := c.Args().First()
+		destination := c.String("output")
+		if destination == "" {
+			destination = source + encryptedFileExtension
+		}
+
+		options, release, err := newSplitOptions(c)
+		if err != nil {
+			return err
+		}
+		defer release()
+		in, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := interruptible(c)
+		defer stop()
+		shards, err := kidwords.SplitFileContext(ctx, out, in, c.Int("shards"), c.Int("quorum"), options...)
+		if err != nil {
+			_ = out.Close()
+			_ = os.Remove(destination)
+			return err
+		}
+		if err = out.Close(); err != nil {
+			return err
+		}
+		if err = printShards(c, shards); err != nil {
+			return err
+		}
+		_, err = fmt.Printf("go run github.com/dkotik/kidwords/cmd/kidwords@%s combine-file %q\n", commit, destination)
+		return err
+	},
+}
+
+var combineFile = &cli.Command{
+	Name:      "combine-file",
+	Usage:     "decrypt a file using a quorum of Shamir's Secret Sharing shards of its key",
+	ArgsUsage: "path to the encrypted file",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "decrypted file path, defaults to the encrypted file path without \"" + encryptedFileExtension + "\" extension",
+		},
+		&cli.BoolFlag{
+			Name:  "stdin",
+			Usage: "read shards from standard input, one per line",
+		},
+		passphraseFlag,
+	},
+	Action: func(c *cli.Context) (err error) {
+		if c.NArg() != 1 {
+			return errors.New("provide exactly one file path")
+		}
+		options, release, err := newCombineOptions(c)
+		if err != nil {
+			return err
+		}
+		defer release()
+		source := c.Args().First()
+		destination := c.String("output")
+		if destination == "" {
+			if !strings.HasSuffix(source, encryptedFileExtension) {
+				return fmt.Errorf("file does not have %q extension, provide the output path", encryptedFileExtension)
+			}
+			destination = strings.TrimSuffix(source, encryptedFileExtension)
+		}
+
+		in, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		var (
+			shards   [][]byte
+			erasures [][]int
+		)
+		if c.Bool("stdin") {
+			shards, erasures, err = readShards(os.Stdin)
+		} else {
+			shards, erasures, err = scanShards()
+		}
+		if err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			defer protect(shard)()
+		}
+
+		out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return err
+		}
+		ctx, stop := interruptible(c)
+		defer stop()
+		if err = kidwords.CombineFileErasuresContext(ctx, out, in, shards, erasures, options...); err != nil {
+			_ = out.Close()
+			_ = os.Remove(destination)
+			return err
+		}
+		if err = out.Close(); err != nil {
+			return err
+		}
+		_, err = fmt.Printf("Decrypted %q\n", destination)
+		return err
+	},
+}
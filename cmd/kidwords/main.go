@@ -4,8 +4,10 @@ Package main is a command line utility for encoding durable and accessible paper
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"runtime/debug"
 
@@ -39,8 +41,13 @@ func main() {
 		Commands: []*cli.Command{
 			split,
 			combine,
+			splitFile,
+			combineFile,
+			vault,
+			slip39,
 			encode,
 			decode,
+			password,
 		},
 	}).Run(os.Args); err != nil {
 		fmt.Printf("Error: %s.\n", err.Error())
@@ -48,6 +55,11 @@ func main() {
 	}
 }
 
+// interruptible returns a context that is canceled by an interrupt signal, so that long operations, like solving a time-lock, stop and clean up instead of leaving partial files behind. Calling the returned function restores the default signal behavior.
+func interruptible(c *cli.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(c.Context, os.Interrupt)
+}
+
 // func main() {
 // 	c := make(chan os.Signal, 1)
 // 	signal.Notify(c, os.Interrupt, os.Kill)
@@ -0,0 +1,16 @@
+package main
+
+import "syscall"
+
+// lockMemory keeps the pages that hold the secret out of swap. Locking is best effort, because the limit on locked memory is often low for unprivileged users.
+func lockMemory(b []byte) {
+	if len(b) > 0 {
+		_ = syscall.Mlock(b)
+	}
+}
+
+func unlockMemory(b []byte) {
+	if len(b) > 0 {
+		_ = syscall.Munlock(b)
+	}
+}
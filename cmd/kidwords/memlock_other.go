@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// lockMemory does nothing on systems where locking memory is not wired up.
+func lockMemory(b []byte) {}
+
+func unlockMemory(b []byte) {}
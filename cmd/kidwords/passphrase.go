@@ -0,0 +1,97 @@
+// License: MIT Open Source
+// Copyright (c) Joe Linoff 2016
+// Wrap around golang.org/x/crypto/ssh/terminal to handle ^C interrupts based on a suggestion by Konstantin Shaposhnikov in
+// this thread: https://groups.google.com/forum/#!topic/golang-nuts/kTVAbtee9UA.
+// Correctly resets terminal echo after ^C interrupts.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/dkotik/kidwords"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+func scanPassword(prompt string) ([]byte, error) {
+	initialTermState, err := terminal.GetState(syscall.Stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restore state in the event of an interrupt.
+	// CITATION: Konstantin Shaposhnikov - https://groups.google.com/forum/#!topic/golang-nuts/kTVAbtee9UA
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, os.Kill)
+	go func() {
+		<-c
+		_ = terminal.Restore(syscall.Stdin, initialTermState)
+		os.Exit(1)
+	}()
+
+	// Now get the password.
+	fmt.Print(prompt)
+	p, err := terminal.ReadPassword(syscall.Stdin)
+	fmt.Println("")
+	if err != nil {
+		return nil, err
+	}
+
+	// Stop looking for ^C on the channel.
+	signal.Stop(c)
+	return p, nil
+}
+
+// Environment variables provide passphrases to scripts that cannot type them into a terminal.
+const (
+	passphraseEnvironmentVariable      = "KIDWORDS_PASSPHRASE"
+	decoyPassphraseEnvironmentVariable = "KIDWORDS_DECOY_PASSPHRASE"
+	decoySecretEnvironmentVariable     = "KIDWORDS_DECOY_SECRET"
+)
+
+// readPassphrase takes the passphrase from the environment or asks for it, twice if it must be confirmed. Call release to wipe it.
+func readPassphrase(name, environmentVariable string, confirm bool) (passphrase []byte, release func(), err error) {
+	if p, ok := os.LookupEnv(environmentVariable); ok {
+		if p == "" {
+			return nil, nil, errors.New(environmentVariable + " is empty")
+		}
+		passphrase = []byte(p)
+		return passphrase, protect(passphrase), nil
+	}
+	if passphrase, err = scanPassword(fmt.Sprintf(" 🔒 %s: ", name)); err != nil {
+		return nil, nil, err
+	}
+	release = protect(passphrase)
+	if len(passphrase) == 0 {
+		release()
+		return nil, nil, fmt.Errorf("%s is empty", strings.ToLower(name))
+	}
+	if confirm {
+		repeated, err := scanPassword(fmt.Sprintf(" 🔒 Repeat %s: ", strings.ToLower(name)))
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		defer protect(repeated)()
+		if !bytes.Equal(repeated, passphrase) {
+			release()
+			return nil, nil, fmt.Errorf("%ss do not match", strings.ToLower(name))
+		}
+	}
+	return passphrase, release, nil
+}
+
+// protect locks the secret in memory, where the system allows it, and returns the function that wipes and unlocks it once the secret is no longer needed.
+func protect(secret []byte) (release func()) {
+	lockMemory(secret)
+	return func() {
+		kidwords.Secret(secret).Wipe()
+		unlockMemory(secret)
+	}
+}
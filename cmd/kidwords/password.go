@@ -1,45 +1,75 @@
-// License: MIT Open Source
-// Copyright (c) Joe Linoff 2016
-// Wrap around golang.org/x/crypto/ssh/terminal to handle ^C interrupts based on a suggestion by Konstantin Shaposhnikov in
-// this thread: https://groups.google.com/forum/#!topic/golang-nuts/kTVAbtee9UA.
-// Correctly resets terminal echo after ^C interrupts.
-
 package main
 
 import (
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
 
-	"golang.org/x/crypto/ssh/terminal"
+	"github.com/dkotik/kidwords"
+	"github.com/urfave/cli/v2"
 )
 
-func scanPassword(prompt string) ([]byte, error) {
-	initialTermState, err := terminal.GetState(syscall.Stdin)
-	if err != nil {
-		return nil, err
-	}
-
-	// Restore state in the event of an interrupt.
-	// CITATION: Konstantin Shaposhnikov - https://groups.google.com/forum/#!topic/golang-nuts/kTVAbtee9UA
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt, os.Kill)
-	go func() {
-		<-c
-		_ = terminal.Restore(syscall.Stdin, initialTermState)
-		os.Exit(1)
-	}()
-
-	// Now get the password.
-	fmt.Print(prompt)
-	p, err := terminal.ReadPassword(syscall.Stdin)
-	fmt.Println("")
-	if err != nil {
-		return nil, err
-	}
+var password = &cli.Command{
+	Name:  "password",
+	Usage: "generate a random passphrase from dictionary words",
+	Flags: append([]cli.Flag{
+		&cli.IntFlag{
+			Name:    "bits",
+			Aliases: []string{"b"},
+			Usage:   "the entropy of the passphrase, which takes one word for every eight bits",
+			Value:   80,
+			Action: func(ctx *cli.Context, n int) error {
+				if n < 1 || n > 1024 {
+					return fmt.Errorf("Flag bits value %d out of range[1-1024]", n)
+				}
+				return nil
+			},
+		},
+		&cli.IntFlag{
+			Name:    "digits",
+			Aliases: []string{"d"},
+			Usage:   "append this many random digits for sites that require them",
+		},
+		&cli.IntFlag{
+			Name:    "symbols",
+			Aliases: []string{"y"},
+			Usage:   "append this many random symbols for sites that require them",
+		},
+		&cli.BoolFlag{
+			Name:  "split",
+			Usage: "split the passphrase into shards right away",
+		},
+	}, splitFlags...),
+	Action: func(c *cli.Context) error {
+		p, err := kidwords.GeneratePassphrase(c.Int("bits"), kidwords.PassphraseCharacters{
+			Digits:  c.Int("digits"),
+			Symbols: c.Int("symbols"),
+		})
+		if err != nil {
+			return err
+		}
+		defer protect(p.Phrase)()
+		fmt.Fprintf(os.Stderr, " 🎲 %.0f bits of entropy\n", p.Entropy)
+		if _, err = os.Stdout.Write(p.Phrase); err != nil {
+			return err
+		}
+		if _, err = fmt.Println(); err != nil {
+			return err
+		}
+		if !c.Bool("split") {
+			return nil
+		}
 
-	// Stop looking for ^C on the channel.
-	signal.Stop(c)
-	return p, nil
+		splitOptions, release, err := newSplitOptions(c)
+		if err != nil {
+			return err
+		}
+		defer release()
+		ctx, stop := interruptible(c)
+		defer stop()
+		shards, err := kidwords.SplitBytesContext(ctx, p.Phrase, c.Int("shards"), c.Int("quorum"), splitOptions...)
+		if err != nil {
+			return err
+		}
+		return printShards(c, shards)
+	},
 }
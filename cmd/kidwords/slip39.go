@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/dkotik/kidwords"
+	"github.com/urfave/cli/v2"
+)
+
+var slip39 = &cli.Command{
+	Name:  "slip39",
+	Usage: "convert SLIP-0039 mnemonic shares to and from shards",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "import",
+			Usage:     "convert a SLIP-0039 mnemonic share into a shard",
+			ArgsUsage: "mnemonic words or \"-\" to take standard input",
+			Action: func(c *cli.Context) error {
+				mnemonic, err := argumentOrStdin(c)
+				if err != nil {
+					return err
+				}
+				shard, err := kidwords.ImportSLIP39(mnemonic)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Println(shard)
+				return err
+			},
+		},
+		{
+			Name:      "export",
+			Usage:     "convert a shard into a SLIP-0039 mnemonic share",
+			ArgsUsage: "shard words or \"-\" to take standard input",
+			Action: func(c *cli.Context) error {
+				shard, err := argumentOrStdin(c)
+				if err != nil {
+					return err
+				}
+				mnemonic, err := kidwords.ExportSLIP39(shard)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Println(mnemonic)
+				return err
+			},
+		},
+	},
+}
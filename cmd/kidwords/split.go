@@ -1,87 +1,133 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/dkotik/kidwords"
+	"github.com/dkotik/kidwords/shamir"
 	"github.com/urfave/cli/v2"
 )
 
-var split = &cli.Command{
-	Name:      "split",
-	Usage:     "split input into Shamir's Secret Sharing shards",
-	ArgsUsage: "\"-\" argument takes standard input",
-	Flags: []cli.Flag{
-		&cli.IntFlag{
-			Name:    "shards",
-			Aliases: []string{"s"},
-			Usage:   "the number of shards to create",
-			Value:   12,
-			Action: func(ctx *cli.Context, n int) error {
-				if n < 2 || n > 256 {
-					return fmt.Errorf("Flag shards value %d out of range[2-256]", n)
-				}
-				return nil
-			},
+var splitFlags = []cli.Flag{
+	&cli.IntFlag{
+		Name:    "shards",
+		Aliases: []string{"s"},
+		Usage:   "the number of shards to create",
+		Value:   12,
+		Action: func(ctx *cli.Context, n int) error {
+			if n < 2 || n > 256 {
+				return fmt.Errorf("Flag shards value %d out of range[2-256]", n)
+			}
+			return nil
 		},
-		&cli.IntFlag{
-			Name:    "quorum",
-			Aliases: []string{"q"},
-			Usage:   "the number of shards required to recover the secret",
-			Value:   4,
-			Action: func(ctx *cli.Context, n int) error {
-				if n < 2 || n > 256 {
-					return fmt.Errorf("Flag quorum value %d out of range[2-256]", n)
-				}
-				return nil
-			},
+	},
+	&cli.IntFlag{
+		Name:    "quorum",
+		Aliases: []string{"q"},
+		Usage:   "the number of shards required to recover the secret",
+		Value:   4,
+		Action: func(ctx *cli.Context, n int) error {
+			if n < 2 || n > 256 {
+				return fmt.Errorf("Flag quorum value %d out of range[2-256]", n)
+			}
+			return nil
 		},
-		&cli.IntFlag{
-			Name:    "columns",
-			Aliases: []string{"c"},
-			Usage:   "the number of table columns in the output grid",
-			Value:   3,
-			Action: func(ctx *cli.Context, n int) error {
-				if n < 1 || n > 12 {
-					return fmt.Errorf("Flag columns value %d out of range[1-12]", n)
-				}
-				return nil
-			},
+	},
+	&cli.IntFlag{
+		Name:    "columns",
+		Aliases: []string{"c"},
+		Usage:   "the number of table columns in the output grid",
+		Value:   3,
+		Action: func(ctx *cli.Context, n int) error {
+			if n < 1 || n > 12 {
+				return fmt.Errorf("Flag columns value %d out of range[1-12]", n)
+			}
+			return nil
 		},
-		&cli.IntFlag{
-			Name:    "wrap",
-			Aliases: []string{"w"},
-			Usage:   "maximum shard line length",
-			Value:   18,
-			Action: func(ctx *cli.Context, n int) error {
-				if n < 4 || n > 128 {
-					return fmt.Errorf("Flag wrap value %d out of range[4-128]", n)
-				}
-				return nil
-			},
+	},
+	&cli.IntFlag{
+		Name:    "wrap",
+		Aliases: []string{"w"},
+		Usage:   "maximum shard line length",
+		Value:   18,
+		Action: func(ctx *cli.Context, n int) error {
+			if n < 4 || n > 128 {
+				return fmt.Errorf("Flag wrap value %d out of range[4-128]", n)
+			}
+			return nil
 		},
 	},
-	Action: func(c *cli.Context) error {
-		input := strings.Join(c.Args().Slice(), " ")
-		if input == "-" {
+	&cli.StringFlag{
+		Name:    "field",
+		Aliases: []string{"f"},
+		Usage:   "secret sharing arithmetic: gf256, prime61, or prime127",
+		Value:   "gf256",
+		Action: func(ctx *cli.Context, field string) error {
+			_, err := fieldBackend(field)
+			return err
+		},
+	},
+	&cli.BoolFlag{
+		Name:    "numbered",
+		Aliases: []string{"n"},
+		Usage:   "number the shards and encode the numbers into the shards for validation",
+	},
+	passphraseFlag,
+	&cli.DurationFlag{
+		Name:  "time-lock",
+		Usage: "require about this much work on this machine to recover the secret after the shards are gathered, like \"12h\"",
+	},
+	&cli.BoolFlag{
+		Name:  "decoy",
+		Usage: "hide a decoy secret that is recovered by a different passphrase, both asked for interactively or taken from " + decoySecretEnvironmentVariable + " and " + decoyPassphraseEnvironmentVariable,
+	},
+	&cli.BoolFlag{
+		Name:  "deniable",
+		Usage: "make room for a decoy secret, so that nobody can tell whether the shards hide one, at the cost of much longer shards",
+	},
+}
 
+var passphraseFlag = &cli.BoolFlag{
+	Name:    "passphrase",
+	Aliases: []string{"p"},
+	Usage:   "require a passphrase in addition to the shards, asked for interactively or taken from " + passphraseEnvironmentVariable,
+}
+
+var split = &cli.Command{
+	Name:      "split",
+	Usage:     "split input into Shamir's Secret Sharing shards",
+	ArgsUsage: "\"-\" argument takes standard input",
+	Flags:     splitFlags,
+	Action: func(c *cli.Context) error {
+		var (
+			secret  = []byte(strings.Join(c.Args().Slice(), " "))
+			release = protect(secret)
+			err     error
+		)
+		if string(secret) == "-" {
+			if secret, release, err = readSecret(os.Stdin); err != nil {
+				return err
+			}
 		}
+		defer release()
 
-		parts := c.Value("shards").(int)
-		threshold := c.Value("quorum").(int)
-		shards, err := kidwords.Split(input, parts, threshold)
+		options, releaseOptions, err := newSplitOptions(c)
 		if err != nil {
 			return err
 		}
-		if _, err = fmt.Printf(" 🔑 Pick any %d shards:\n", threshold); err != nil {
+		defer releaseOptions()
+		ctx, stop := interruptible(c)
+		defer stop()
+		shards, err := kidwords.SplitBytesContext(ctx, secret, c.Int("shards"), c.Int("quorum"), options...)
+		if err != nil {
 			return err
 		}
-
-		columns := c.Value("columns").(int)
-		wrap := c.Value("wrap").(int)
-		if _, err = shards.Grid(columns, wrap).Write(os.Stdout); err != nil {
+		if err = printShards(c, shards); err != nil {
 			return err
 		}
 
@@ -98,3 +144,109 @@ var split = &cli.Command{
 		return err
 	},
 }
+
+// maxSecretSize limits the secret taken from standard input, which is read into a single buffer, so that growing the buffer does not leave copies of the secret behind.
+const maxSecretSize = 1 << 16
+
+// readSecret reads the secret as is, including any trailing new line. Call release to wipe it.
+func readSecret(r io.Reader) (secret []byte, release func(), err error) {
+	buffer := make([]byte, maxSecretSize+1)
+	release = protect(buffer)
+	n, err := io.ReadFull(r, buffer)
+	switch {
+	case err == nil:
+		release()
+		return nil, nil, fmt.Errorf("secret is longer than %d bytes", maxSecretSize)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return buffer[:n], release, nil
+	default:
+		release()
+		return nil, nil, err
+	}
+}
+
+func fieldBackend(field string) (shamir.Backend, error) {
+	switch field {
+	case "gf256":
+		return shamir.GF256{}, nil
+	case "prime61":
+		return shamir.NewPrimeField(shamir.Mersenne61)
+	case "prime127":
+		return shamir.NewPrimeField(shamir.Mersenne127)
+	default:
+		return nil, fmt.Errorf("Flag field value %q is not one of gf256, prime61, or prime127", field)
+	}
+}
+
+func newSplitOptions(c *cli.Context) (options []kidwords.SplitOption, release func(), err error) {
+	var releases []func()
+	release = func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+	if c.Bool("numbered") {
+		options = append(options, kidwords.WithSequentialShards())
+	}
+	backend, err := fieldBackend(c.String("field"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.Bool("passphrase") {
+		passphrase, releasePassphrase, err := readPassphrase("Passphrase", passphraseEnvironmentVariable, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		releases = append(releases, releasePassphrase)
+		options = append(options, kidwords.WithPassphrase(passphrase))
+	}
+	if c.Bool("decoy") {
+		if !c.Bool("passphrase") {
+			return nil, nil, errors.New("decoy secret requires a passphrase")
+		}
+		secret, releaseSecret, err := readPassphrase("Decoy secret", decoySecretEnvironmentVariable, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		releases = append(releases, releaseSecret)
+		passphrase, releasePassphrase, err := readPassphrase("Decoy passphrase", decoyPassphraseEnvironmentVariable, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		releases = append(releases, releasePassphrase)
+		options = append(options, kidwords.WithDecoy(passphrase, secret))
+	} else if c.Bool("deniable") {
+		if !c.Bool("passphrase") {
+			return nil, nil, errors.New("deniability requires a passphrase")
+		}
+		options = append(options, kidwords.WithDeniability())
+	}
+	if c.IsSet("time-lock") {
+		lock, err := kidwords.CalibrateTimeLock(c.Duration("time-lock"), kidwords.DefaultTimeLockMemoryCost)
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Fprintf(os.Stderr, " ⏳ Creating a time-lock of %d segments with %d steps each, which takes about %s...\n",
+			lock.Segments, lock.Iterations, c.Duration("time-lock")/time.Duration(lock.Segments))
+		options = append(options, kidwords.WithTimeLock(lock))
+	}
+	return append(options, kidwords.WithBackend(backend)), release, nil
+}
+
+func printShards(c *cli.Context, shards kidwords.Shards) (err error) {
+	if c.Bool("numbered") {
+		for i, shard := range shards {
+			shards[i] = fmt.Sprintf("#%d %s", i+1, shard)
+		}
+	}
+	if _, err = fmt.Printf(" 🔑 Pick any %d shards:\n", c.Int("quorum")); err != nil {
+		return err
+	}
+	_, err = shards.Grid(c.Int("columns"), c.Int("wrap")).Write(os.Stdout)
+	return err
+}
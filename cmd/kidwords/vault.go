@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dkotik/kidwords"
+	"github.com/dkotik/kidwords/shamir"
+	"github.com/urfave/cli/v2"
+)
+
+var vault = &cli.Command{
+	Name:  "vault",
+	Usage: "convert HashiCorp Vault unseal keys to and from shards",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "import",
+			Usage:     "convert a base64 or hex unseal key into a shard",
+			ArgsUsage: "unseal key or \"-\" to take standard input",
+			Action: func(c *cli.Context) error {
+				key, err := argumentOrStdin(c)
+				if err != nil {
+					return err
+				}
+				share, err := shamir.ParseVaultKey(key)
+				if err != nil {
+					return err
+				}
+				shard, err := kidwords.ImportShare(share)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Println(shard)
+				return err
+			},
+		},
+		{
+			Name:      "export",
+			Usage:     "convert a shard into a base64 unseal key",
+			ArgsUsage: "shard words or \"-\" to take standard input",
+			Action: func(c *cli.Context) error {
+				shard, err := argumentOrStdin(c)
+				if err != nil {
+					return err
+				}
+				share, err := kidwords.ExportShare(shard)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Println(shamir.FormatVaultKey(share))
+				return err
+			},
+		},
+	},
+}
+
+func argumentOrStdin(c *cli.Context) (string, error) {
+	input := strings.Join(c.Args().Slice(), " ")
+	if input == "-" {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		input = string(b)
+	}
+	if input = strings.TrimSpace(input); input == "" {
+		return "", errors.New("input is empty")
+	}
+	return input, nil
+}
@@ -0,0 +1,97 @@
+package kidwords
+
+import (
+	"bytes"
+	"compress/flate"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Compression codecs recorded in [Container] flags.
+const (
+	codecNone = iota
+	codecFlate
+	// codecWords is DEFLATE primed with the BIP39 word list, which shortens mnemonics, passphrases, and notes made of common English words.
+	codecWords
+)
+
+//go:embed bip39/dictionary.txt
+var bip39Dictionary string
+
+var wordsCodecDictionary = sync.OnceValue(func() []byte {
+	words := make([]string, 0, 2048)
+	for _, line := range strings.Split(bip39Dictionary, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "//") {
+			words = append(words, line)
+		}
+	}
+	return []byte(strings.Join(words, " "))
+})
+
+func compressWith(codec int, p []byte) ([]byte, error) {
+	var (
+		b   = &bytes.Buffer{}
+		w   *flate.Writer
+		err error
+	)
+	switch codec {
+	case codecFlate:
+		w, err = flate.NewWriter(b, flate.BestCompression)
+	case codecWords:
+		w, err = flate.NewWriterDict(b, flate.BestCompression, wordsCodecDictionary())
+	default:
+		return nil, fmt.Errorf("compression codec %d is not supported", codec)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(p); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// compressPayload tries every codec and returns the shortest result. Short random data, like keys and shards, usually grows when compressed, so it is kept as is.
+func compressPayload(p []byte) (codec int, compressed []byte, err error) {
+	codec, compressed = codecNone, p
+	for _, candidate := range []int{codecFlate, codecWords} {
+		b, err := compressWith(candidate, p)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(b) < len(compressed) {
+			codec, compressed = candidate, b
+		}
+	}
+	return codec, compressed, nil
+}
+
+func decompressPayload(codec int, p []byte) ([]byte, error) {
+	var r io.ReadCloser
+	switch codec {
+	case codecNone:
+		return p, nil
+	case codecFlate:
+		r = flate.NewReader(bytes.NewReader(p))
+	case codecWords:
+		r = flate.NewReaderDict(bytes.NewReader(p), wordsCodecDictionary())
+	default:
+		return nil, fmt.Errorf("compression codec %d is not supported", codec)
+	}
+	defer r.Close()
+	b, err := io.ReadAll(io.LimitReader(r, containerMaxLength+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress container: %w", err)
+	}
+	if len(b) > containerMaxLength {
+		return nil, errors.New("decompressed container is too large")
+	}
+	return b, nil
+}
@@ -0,0 +1,79 @@
+package kidwords
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Name    string
+		Payload []byte
+		Codec   int
+	}{
+		{Name: "random key", Payload: key, Codec: codecNone},
+		{Name: "repetitive text", Payload: bytes.Repeat([]byte("0123456789"), 20), Codec: codecFlate},
+		{Name: "mnemonic", Payload: []byte("goddess return math panther sustain black fatigue tortoise vast steel fiction scare"), Codec: codecWords},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			codec, compressed, err := compressPayload(c.Payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if codec != c.Codec {
+				t.Fatalf("picked codec %d instead of %d", codec, c.Codec)
+			}
+
+			plain := &bytes.Buffer{}
+			w, err := NewContainerWriter(plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = w.Write(c.Payload); err != nil {
+				t.Fatal(err)
+			}
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			b := &bytes.Buffer{}
+			if w, err = NewContainerWriter(b, WithCompression()); err != nil {
+				t.Fatal(err)
+			}
+			if _, err = w.Write(c.Payload); err != nil {
+				t.Fatal(err)
+			}
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if c.Codec != codecNone && len(strings.Fields(b.String())) >= len(strings.Fields(plain.String())) {
+				t.Fatalf("compressed payload of %d bytes did not shorten words", len(compressed))
+			}
+
+			decoded, err := ReadContainer(strings.NewReader(b.String()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decoded.Payload, c.Payload) {
+				t.Fatalf("decompressed %q instead of %q", decoded.Payload, c.Payload)
+			}
+			if decoded.Compress != (c.Codec != codecNone) {
+				t.Fatal("container does not report compression")
+			}
+		})
+	}
+
+	if _, err := NewWriter(nil, WithCompression()); err == nil {
+		t.Fatal("compression was accepted without a container")
+	}
+	if _, err := Split("somethingElse", 3, 2, WithCompression()); err == nil {
+		t.Fatal("compression was accepted for shards")
+	}
+}
@@ -0,0 +1,193 @@
+package kidwords
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Container frames a payload, so that decoders can tell what the words hold and whether they were copied correctly. The frame starts with a magic byte, followed by a header byte, which holds the container version in the high three bits and feature flags in the low five bits, and the payload length as a variable integer. The payload is followed by its CRC-32 checksum using [ChecksumTable], which covers the whole frame.
+const (
+	containerMagic       = 0x4b // K
+	containerVersion     = 1
+	containerVersionMask = 0b11100000
+	containerFlagMask    = 0b00011111
+	containerMaxLength   = 1 << 24
+
+	// containerCodecMask selects the bits that hold the compression codec of the payload.
+	containerCodecMask  = 0b00000110
+	containerCodecShift = 1
+
+	// containerKindMask selects the bits that hold the [PayloadKind].
+	containerKindMask  = 0b00011000
+	containerKindShift = 3
+)
+
+// PayloadKind tells what a [Container] holds, so that decoders do not mistake a shard for a key.
+type PayloadKind uint8
+
+const (
+	// PayloadData is any data, like a note or a file.
+	PayloadData PayloadKind = iota
+	// PayloadKey is a raw secret key.
+	PayloadKey
+	// PayloadShard is a shard created by [Split] or [SplitPolicy] and decoded by [ToBytes].
+	PayloadShard
+	// PayloadChecksummed is data that carries its own checksums written by [ChecksumWriter].
+	PayloadChecksummed
+)
+
+func (k PayloadKind) String() string {
+	switch k {
+	case PayloadData:
+		return "data"
+	case PayloadKey:
+		return "key"
+	case PayloadShard:
+		return "shard"
+	case PayloadChecksummed:
+		return "checksummed data"
+	default:
+		return fmt.Sprintf("payload kind #%d", uint8(k))
+	}
+}
+
+// Container is a framed payload.
+type Container struct {
+	// Kind tells what the payload holds. It is recorded in the header.
+	Kind PayloadKind
+	// Compress picks the compression codec that makes the payload shortest, if any. The codec is recorded in the header, so decoding restores the payload automatically.
+	Compress bool
+	// Payload holds the framed data.
+	Payload []byte
+}
+
+func (c *Container) MarshalBinary() ([]byte, error) {
+	if len(c.Payload) > containerMaxLength {
+		return nil, fmt.Errorf("container payload cannot exceed %d bytes", containerMaxLength)
+	}
+	if c.Kind > PayloadChecksummed {
+		return nil, fmt.Errorf("%s is not supported", c.Kind)
+	}
+	flags := byte(c.Kind) << containerKindShift
+	payload := c.Payload
+	if c.Compress {
+		codec, compressed, err := compressPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		flags |= byte(codec) << containerCodecShift
+		payload = compressed
+	}
+	b := make([]byte, 0, 2+binary.MaxVarintLen32+len(payload)+crc32.Size)
+	b = append(b, containerMagic, containerVersion<<5|flags)
+	b = binary.AppendUvarint(b, uint64(len(payload)))
+	b = append(b, payload...)
+	return binary.BigEndian.AppendUint32(b, crc32.Checksum(b, ChecksumTable)), nil
+}
+
+func (c *Container) UnmarshalBinary(b []byte) error {
+	if len(b) < 3+crc32.Size {
+		return errors.New("container is too short")
+	}
+	if b[0] != containerMagic {
+		return errors.New("words do not start with a container")
+	}
+	if version := b[1] & containerVersionMask >> 5; version != containerVersion {
+		return fmt.Errorf("container version %d is not supported", version)
+	}
+	flags := b[1] & containerFlagMask
+	if flags&^(containerCodecMask|containerKindMask) != 0 {
+		return fmt.Errorf("unknown container flags: %08b", flags)
+	}
+	length, n := binary.Uvarint(b[2:])
+	if n <= 0 || length > containerMaxLength {
+		return errors.New("container length is corrupt")
+	}
+	end := 2 + n + int(length)
+	if len(b) < end+crc32.Size {
+		return fmt.Errorf("container is truncated: %d of %d bytes", len(b), end+crc32.Size)
+	}
+	if len(b) > end+crc32.Size {
+		return errors.New("container is followed by unexpected data")
+	}
+	if binary.BigEndian.Uint32(b[end:]) != crc32.Checksum(b[:end], ChecksumTable) {
+		return errors.New("container checksum does not match")
+	}
+	codec := int(flags&containerCodecMask) >> containerCodecShift
+	payload, err := decompressPayload(codec, b[2+n:end])
+	if err != nil {
+		return err
+	}
+	c.Kind = PayloadKind(flags&containerKindMask) >> containerKindShift
+	c.Compress = codec != codecNone
+	c.Payload = payload
+	return nil
+}
+
+// ContainerWriter collects a payload and writes it framed in a [Container] as Kid Words when closed.
+type ContainerWriter struct {
+	container Container
+	buffer    bytes.Buffer
+	w         *Writer
+	closed    bool
+}
+
+// NewContainerWriter frames everything written to it into a [Container] and encodes it into [io.Writer] on [ContainerWriter.Close]. The payload is compressed when [WithCompression] option is set and marked by [WithPayloadKind] option.
+func NewContainerWriter(w io.Writer, withOptions ...WriterOption) (*ContainerWriter, error) {
+	o, err := newWriterOptions(withOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &ContainerWriter{
+		container: Container{Kind: o.kind, Compress: o.compress},
+		w:         newWriter(w, o),
+	}, nil
+}
+
+func (c *ContainerWriter) Write(p []byte) (int, error) {
+	if c.closed {
+		return 0, errors.New("container writer is closed")
+	}
+	if c.buffer.Len()+len(p) > containerMaxLength {
+		return 0, fmt.Errorf("container payload cannot exceed %d bytes", containerMaxLength)
+	}
+	return c.buffer.Write(p)
+}
+
+// Close encodes the framed payload.
+func (c *ContainerWriter) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.container.Payload = c.buffer.Bytes()
+	b, err := c.container.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if _, err = c.w.Write(b); err != nil {
+		return err
+	}
+	return c.w.Close()
+}
+
+// ReadContainer decodes Kid Words from [io.Reader] into a [Container] and verifies its checksum.
+func ReadContainer(r io.Reader, withOptions ...ReaderOption) (*Container, error) {
+	reader, err := NewReader(r, withOptions...)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	c := &Container{}
+	if err = c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
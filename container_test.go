@@ -0,0 +1,62 @@
+package kidwords
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestContainer(t *testing.T) {
+	for _, c := range []Container{
+		{Kind: PayloadKey, Payload: []byte("raw key")},
+		{Kind: PayloadShard, Payload: bytes.Repeat([]byte{0xfe}, 300)},
+		{Kind: PayloadChecksummed, Payload: []byte("checksummed")},
+		{Kind: PayloadData, Compress: true, Payload: bytes.Repeat([]byte("text "), 40)},
+		{Payload: []byte{}},
+	} {
+		options := []WriterOption{WithPayloadKind(c.Kind)}
+		if c.Compress {
+			options = append(options, WithCompression())
+		}
+		b := &bytes.Buffer{}
+		w, err := NewContainerWriter(b, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write(c.Payload); err != nil {
+			t.Fatal(err)
+		}
+		if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := ReadContainer(strings.NewReader(b.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Kind != c.Kind || decoded.Compress != c.Compress || !bytes.Equal(decoded.Payload, c.Payload) {
+			t.Fatalf("decoded container %+v does not match %+v", decoded, c)
+		}
+
+		words := strings.Fields(b.String())
+		if _, err = ReadContainer(strings.NewReader(strings.Join(words[:len(words)-1], " "))); err == nil {
+			t.Fatal("truncated container was accepted")
+		}
+		words[2], words[3] = words[3], words[2]
+		if words[2] != words[3] {
+			if _, err = ReadContainer(strings.NewReader(strings.Join(words, " "))); err == nil {
+				t.Fatal("corrupt container was accepted")
+			}
+		}
+	}
+
+	if _, err := ReadContainer(strings.NewReader("area army atom aunt baby back ball")); err == nil {
+		t.Fatal("words without a container were accepted")
+	}
+	if _, err := (&Container{Kind: PayloadChecksummed + 1}).MarshalBinary(); err == nil {
+		t.Fatal("unknown payload kind was accepted")
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, WithPayloadKind(PayloadKey)); err == nil {
+		t.Fatal("payload kind was accepted without a container")
+	}
+}
@@ -0,0 +1,86 @@
+package kidwords
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	deriveSeedSize      = 32
+	maxDerivedKeySize   = 255 * sha256.Size // the limit of HKDF-SHA256
+	minDeriveSaltSize   = 8                 // the least that Argon2 allows
+	deriveKeyInfo       = "kidwords key"
+	derivePathSeparator = "/"
+)
+
+// DeriveKey turns a phrase of Kid Words into a key of the given length. The words are decoded first, so the phrase may be typed in any case or arrangement, but characters other than words are ignored as by [Reader], including the digits and symbols added by [GeneratePassphrase]. Erased words cannot be recovered, so placeholders accepted by [WithErasures] are rejected. The decoded bytes are stretched by Argon2id with the given salt and parameters, usually [DefaultArgonParameters], into a seed. The seed is never used as a key directly. Nothing records the salt or the parameters, so the same ones must be given every time, like the salt kept by store.ArgonHash.
+//
+// The salt should be unique to the owner of the phrase, like a random value kept next to the encrypted data or an account name, so that guessing phrases for one owner does not help with another. It must be at least eight bytes long.
+//
+// The path, like "email/work", selects a purpose. Each segment separated by "/" derives the next seed from the previous one by HKDF-SHA256, so one paper phrase yields unrelated keys for different paths, and the same key for the same path every time. An empty path selects the root. Generate phrases of at least 80 bits, because their entropy is the only protection against guessing once the salt is known.
+func DeriveKey(phrase, salt []byte, path string, length int, argon ArgonParameters, withOptions ...ReaderOption) (Secret, error) {
+	if length < 1 || length > maxDerivedKeySize {
+		return nil, fmt.Errorf("derived key length %d is out of range [1-%d]", length, maxDerivedKeySize)
+	}
+	if len(salt) < minDeriveSaltSize {
+		return nil, fmt.Errorf("salt must be at least %d bytes long", minDeriveSaltSize)
+	}
+	var segments []string
+	if path != "" {
+		segments = strings.Split(path, derivePathSeparator)
+		for i, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("derivation path segment %d is empty", i+1)
+			}
+		}
+	}
+	if err := argon.Validate(); err != nil {
+		return nil, err
+	}
+
+	r, err := NewReader(bytes.NewReader(phrase), withOptions...)
+	if err != nil {
+		return nil, err
+	}
+	// every word takes at least one character, so the decoded words fit into a buffer of the phrase length, which never has to grow and leave copies behind
+	words := make(Secret, len(phrase))
+	defer words.Wipe()
+	n, err := io.ReadFull(r, words)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	words = words[:n]
+	if len(words) == 0 {
+		return nil, errors.New("phrase has no words")
+	}
+	if len(r.Erasures()) > 0 {
+		return nil, errors.New("erased words cannot be recovered to derive a key")
+	}
+
+	seed := Secret(argon2.IDKey(words, salt, argon.TimeCost, argon.MemoryCost, argon.ParallelThreads, deriveSeedSize))
+	for _, segment := range segments {
+		next := make(Secret, deriveSeedSize)
+		_, err = io.ReadFull(hkdf.New(sha256.New, seed, nil, []byte(segment)), next)
+		seed.Wipe()
+		if err != nil {
+			next.Wipe()
+			return nil, err
+		}
+		seed = next
+	}
+	defer seed.Wipe()
+
+	key := make(Secret, length)
+	if _, err = io.ReadFull(hkdf.Expand(sha256.New, seed, []byte(deriveKeyInfo)), key); err != nil {
+		key.Wipe()
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,69 @@
+package kidwords
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	cheap := ArgonParameters{TimeCost: 1, MemoryCost: 64, ParallelThreads: 1}
+	phrase := []byte("roof wind fish save gain math base nose girl snow")
+	salt := []byte("alice@example.com")
+	key, err := DeriveKey(phrase, salt, "email/work", 32, cheap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 32 {
+		t.Fatalf("derived %d bytes instead of 32", len(key))
+	}
+
+	retyped, err := DeriveKey([]byte("Roof Wind FishSave\ngain math base nose girl snow"), salt, "email/work", 32, cheap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, retyped) {
+		t.Fatal("retyped phrase derived a different key")
+	}
+	longer, err := DeriveKey(phrase, salt, "email/work", 64, cheap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, longer[:32]) {
+		t.Fatal("longer key does not extend the shorter one")
+	}
+
+	for _, path := range []string{"", "email", "email/home", "work/email"} {
+		other, err := DeriveKey(phrase, salt, path, 32, cheap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(key, other) {
+			t.Fatalf("path %q derived the same key as %q", path, "email/work")
+		}
+	}
+
+	for _, path := range []string{"/email", "email//work", "email/"} {
+		if _, err = DeriveKey(phrase, salt, path, 32, cheap); err == nil {
+			t.Fatalf("path %q with an empty segment was accepted", path)
+		}
+	}
+	if _, err = DeriveKey([]byte("123"), salt, "", 32, cheap); err == nil {
+		t.Fatal("phrase without words was accepted")
+	}
+	if _, err = DeriveKey([]byte("roof ? fish"), salt, "", 32, cheap, WithErasures()); err == nil {
+		t.Fatal("erased word was accepted")
+	}
+	other, err := DeriveKey(phrase, []byte("bob@example.com"), "email/work", 32, cheap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(key, other) {
+		t.Fatal("different salt derived the same key")
+	}
+	if _, err = DeriveKey(phrase, []byte("short"), "email/work", 32, cheap); err == nil {
+		t.Fatal("short salt was accepted")
+	}
+	if _, err = DeriveKey(phrase, salt, "", 32, ArgonParameters{}); err == nil {
+		t.Fatal("zero Argon parameters were accepted")
+	}
+}
@@ -0,0 +1,172 @@
+package kidwords
+
+import (
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/dkotik/kidwords/dictionary"
+)
+
+// Encoding is a Kid Words encoding defined by a dictionary, a separator, and a checksum policy. It mirrors [encoding/base64.Encoding], so that both ends of a conversation can share one value instead of repeating [WriterOption] and [ReaderOption] lists.
+type Encoding struct {
+	dictionary *dictionary.Dictionary
+	matcher    *wordMatcher
+	separator  string
+	checksum   bool
+	minWordLen int
+	maxWordLen int
+}
+
+// StdEncoding uses [dictionary.EnglishFourLetterNouns] separated by spaces without a checksum, which matches [FromBytes] and [ToBytes].
+var StdEncoding = NewEncoding(&dictionary.EnglishFourLetterNouns)
+
+// NewEncoding returns a new space-separated Encoding defined by the dictionary. It panics if the dictionary is not valid.
+func NewEncoding(d *dictionary.Dictionary) *Encoding {
+	if err := d.Validate(); err != nil {
+		panic(err)
+	}
+	e := &Encoding{
+		dictionary: d,
+		separator:  " ",
+		minWordLen: len(d[0]),
+	}
+	e.matcher = newWordMatcher(d.Reverse(), false)
+	for _, word := range d {
+		for _, r := range word {
+			if !unicode.IsLetter(r) {
+				panic("dictionary word " + word + " contains a character that is not a letter")
+			}
+		}
+		e.minWordLen = min(e.minWordLen, len(word))
+		e.maxWordLen = max(e.maxWordLen, len(word))
+	}
+	return e
+}
+
+// WithSeparator creates a new encoding identical to e except that words are joined by the separator. It panics if the separator contains letters, which would be mistaken for words.
+func (e Encoding) WithSeparator(separator string) *Encoding {
+	for _, r := range separator {
+		if unicode.IsLetter(r) {
+			panic("separator cannot contain letters")
+		}
+	}
+	e.separator = separator
+	return &e
+}
+
+// WithChecksum creates a new encoding identical to e except that the data is followed by its CRC-32 checksum, which decoding verifies.
+func (e Encoding) WithChecksum() *Encoding {
+	e.checksum = true
+	return &e
+}
+
+func (e *Encoding) checksumSize() int {
+	if e.checksum {
+		return crc32.Size
+	}
+	return 0
+}
+
+// EncodedLen returns the maximum length in bytes of the encoding of n bytes of data. The length is exact for dictionaries of words of the same length.
+func (e *Encoding) EncodedLen(n int) int {
+	words := n + e.checksumSize()
+	if words == 0 {
+		return 0
+	}
+	return words*e.maxWordLen + (words-1)*len(e.separator)
+}
+
+// DecodedLen returns the maximum length in bytes of the data decoded from n bytes of encoded words.
+func (e *Encoding) DecodedLen(n int) int {
+	words := (n + len(e.separator)) / (e.minWordLen + len(e.separator))
+	return max(words-e.checksumSize(), 0)
+}
+
+// EncodeToString returns the encoding of src.
+func (e *Encoding) EncodeToString(src []byte) string {
+	b := bytes.Buffer{}
+	b.Grow(e.EncodedLen(len(src)))
+	w := e.NewEncoder(&b)
+	_, _ = w.Write(src)
+	_ = w.Close()
+	return b.String()
+}
+
+// DecodeString returns the bytes represented by the words in s.
+func (e *Encoding) DecodeString(s string) ([]byte, error) {
+	b := bytes.NewBuffer(make([]byte, 0, e.DecodedLen(len(s))))
+	if _, err := io.Copy(b, e.NewDecoder(strings.NewReader(s))); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// NewEncoder returns a new Kid Words stream encoder. Data written to the returned writer is encoded and written to w. The caller must close the encoder to flush the checksum.
+func (e *Encoding) NewEncoder(w io.Writer) io.WriteCloser {
+	encoder := &encoder{w: &wordEncoder{encoding: e, w: w}}
+	if e.checksum {
+		encoder.checksum = ChecksumWriter(encoder.w)
+		encoder.w = encoder.checksum
+	}
+	return encoder
+}
+
+type encoder struct {
+	w        io.Writer
+	checksum io.WriteCloser
+	closed   bool
+}
+
+func (e *encoder) Write(p []byte) (n int, err error) {
+	if e.closed {
+		return 0, errors.New("encoder is closed")
+	}
+	return e.w.Write(p)
+}
+
+// Close writes the checksum, when the encoding has one.
+func (e *encoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.checksum != nil {
+		return e.checksum.Close()
+	}
+	return nil
+}
+
+// wordEncoder writes every byte as a dictionary word.
+type wordEncoder struct {
+	encoding *Encoding
+	w        io.Writer
+	started  bool
+}
+
+func (e *wordEncoder) Write(p []byte) (n int, err error) {
+	buffer := make([]byte, 0, len(p)*(e.encoding.maxWordLen+len(e.encoding.separator)))
+	for _, c := range p {
+		if e.started {
+			buffer = append(buffer, e.encoding.separator...)
+		}
+		e.started = true
+		buffer = append(buffer, e.encoding.dictionary[c]...)
+	}
+	if _, err = e.w.Write(buffer); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// NewDecoder constructs a new Kid Words stream decoder, which is as tolerant of case, Unicode normalization, and words typed together as [Reader]. When the encoding has a checksum, the decoder holds back the last bytes and returns an error at the end of the stream if the checksum does not match.
+func (e *Encoding) NewDecoder(r io.Reader) io.Reader {
+	reader := newReader(r, e.matcher)
+	if !e.checksum {
+		return reader
+	}
+	return newChecksumReader(reader)
+}
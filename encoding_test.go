@@ -0,0 +1,121 @@
+package kidwords
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEncoding(t *testing.T) {
+	data := []byte("test by writing something")
+	for _, e := range []*Encoding{
+		StdEncoding,
+		StdEncoding.WithSeparator("-"),
+		StdEncoding.WithSeparator("\n").WithChecksum(),
+	} {
+		encoded := e.EncodeToString(data)
+		if len(encoded) != e.EncodedLen(len(data)) {
+			t.Fatalf("encoded length %d does not match %d", len(encoded), e.EncodedLen(len(data)))
+		}
+		if n := e.DecodedLen(len(encoded)); n != len(data) {
+			t.Fatalf("decoded length %d does not match %d", n, len(data))
+		}
+		decoded, err := e.DecodeString(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Fatalf("decoded %q instead of %q", decoded, data)
+		}
+
+		b := &bytes.Buffer{}
+		w := e.NewEncoder(b)
+		for _, c := range data {
+			if _, err = w.Write([]byte{c}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != encoded {
+			t.Fatalf("stream encoding %q does not match %q", b.String(), encoded)
+		}
+		streamed, err := io.ReadAll(e.NewDecoder(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(streamed, data) {
+			t.Fatalf("stream decoded %q instead of %q", streamed, data)
+		}
+	}
+
+	if StdEncoding.EncodeToString(data) != strings.TrimSpace(must(FromBytes(data))) {
+		t.Fatal("standard encoding does not match FromBytes")
+	}
+
+	checked := StdEncoding.WithChecksum()
+	words := strings.Fields(checked.EncodeToString(data))
+	words[0], words[1] = words[1], words[0]
+	if _, err := checked.DecodeString(strings.Join(words, " ")); err == nil {
+		t.Fatal("checksum did not catch swapped words")
+	}
+	if _, err := checked.DecodeString("area army"); err == nil {
+		t.Fatal("checksum did not catch missing words")
+	}
+}
+
+func TestEncodingChecksumStream(t *testing.T) {
+	data := bytes.Repeat([]byte("long stream of data "), 100)
+	checked := StdEncoding.WithChecksum()
+	encoded := checked.EncodeToString(data)
+
+	raw, err := StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remainder, ok := ChecksumChop(raw); !ok || !bytes.Equal(remainder, data) {
+		t.Fatal("checksum does not match ChecksumChop")
+	}
+
+	for _, r := range []io.Reader{
+		checked.NewDecoder(strings.NewReader(encoded)),
+		iotest.OneByteReader(checked.NewDecoder(strings.NewReader(encoded))),
+		checked.NewDecoder(iotest.OneByteReader(strings.NewReader(encoded))),
+	} {
+		decoded, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Fatal("decoded stream does not match")
+		}
+	}
+
+	words := strings.Fields(encoded)
+	words[len(words)-1] = words[0]
+	if _, err = io.ReadAll(checked.NewDecoder(strings.NewReader(strings.Join(words, " ")))); err == nil {
+		t.Fatal("corrupt checksum was accepted")
+	}
+}
+
+func must(s string, err error) string {
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func ExampleEncoding() {
+	e := StdEncoding.WithSeparator("-").WithChecksum()
+	words := e.EncodeToString([]byte("marvel"))
+	fmt.Println(words)
+	b, err := e.DecodeString(words)
+	fmt.Println(string(b), err)
+	// Output:
+	// hole-gold-hush-item-half-hint-rose-gnat-pack-grid
+	// marvel <nil>
+}
@@ -0,0 +1,228 @@
+package kidwords
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/dkotik/kidwords/shamir"
+)
+
+// Shard envelope prefixes each shard payload with a header byte, which holds the envelope version in the high three bits and feature flags in the low five bits. Flags that did not fit are kept in a second byte, which follows the header when [envelopeExtended] is set, and are numbered from the ninth bit on.
+const (
+	envelopeVersion     = 1
+	envelopeVersionMask = 0b11100000
+	envelopeFlagMask    = 0b00001111
+
+	// envelopeExtended indicates that the header is followed by the byte of extended flags. It is never set in [envelope.flags].
+	envelopeExtended     = 1 << 4
+	envelopeExtendedMask = 0b00000011 << 8
+
+	// envelopeGrouped indicates that the shard belongs to a [shamir.Policy]: the header is followed by path depth and a member, threshold and weight byte for each [shamir.Step].
+	envelopeGrouped = 1 << 0
+
+	// envelopeBackend indicates that the shard was not created by [shamir.GF256]: the header is followed by a backend identifier byte.
+	envelopeBackend = 1 << 1
+
+	// envelopeSealed indicates that the shared secret was encrypted with a passphrase by [WithPassphrase] option.
+	envelopeSealed = 1 << 2
+
+	// envelopeTimeLocked indicates that the shared secret is wrapped into a puzzle by [WithTimeLock] option.
+	envelopeTimeLocked = 1 << 3
+
+	// envelopeThreshold indicates that the header is followed by the number of shards required to recover the secret, which lets [CombineErasures] check that every erased byte is still kept by enough shards.
+	envelopeThreshold = 1 << 8
+
+	// envelopeSequential indicates that the shard tags are the shard numbers assigned by [WithSequentialShards] option, which [ShardNumber] reports.
+	envelopeSequential = 1 << 9
+)
+
+// Secret sharing backend identifiers recorded in shard envelopes.
+const (
+	backendGF256       = 0
+	backendMersenne61  = 1
+	backendMersenne127 = 2
+	backendSLIP39      = 3
+	backendPrime       = 4 // followed by the digest of the custom prime
+	backendCustom      = 255
+
+	backendDigestSize = 4
+)
+
+func backendID(b shamir.Backend) uint8 {
+	switch b := b.(type) {
+	case nil, shamir.GF256, *shamir.GF256:
+		return backendGF256
+	case *shamir.PrimeField:
+		prime := b.Prime()
+		if prime.Cmp(shamir.Mersenne61) == 0 {
+			return backendMersenne61
+		}
+		if prime.Cmp(shamir.Mersenne127) == 0 {
+			return backendMersenne127
+		}
+		return backendPrime
+	}
+	return backendCustom
+}
+
+// backendDigest identifies the custom prime of a [shamir.PrimeField] by the first bytes of its SHA-256 hash, so that [Combine] can tell it from another prime. Other backends need no digest.
+func backendDigest(b shamir.Backend) []byte {
+	if backendID(b) != backendPrime {
+		return nil
+	}
+	digest := sha256.Sum256(b.(*shamir.PrimeField).Prime().Bytes())
+	return digest[:backendDigestSize]
+}
+
+// backendByID returns the backend recorded in shard envelope, preferring the one provided to [Combine] by [WithBackend] option.
+func backendByID(id uint8, digest []byte, provided shamir.Backend) (shamir.Backend, error) {
+	if provided != nil {
+		if expected := backendID(provided); expected != id {
+			return nil, fmt.Errorf("provided secret sharing backend #%d does not match shard backend #%d", expected, id)
+		}
+		if !bytes.Equal(backendDigest(provided), digest) {
+			return nil, errors.New("provided prime field does not match the prime recorded in the shards")
+		}
+		return provided, nil
+	}
+	switch id {
+	case backendGF256:
+		return shamir.GF256{}, nil
+	case backendMersenne61:
+		return shamir.NewPrimeField(shamir.Mersenne61)
+	case backendMersenne127:
+		return shamir.NewPrimeField(shamir.Mersenne127)
+	case backendSLIP39:
+		return nil, errors.New("SLIP-39 shards must be converted back to mnemonics by ExportSLIP39 and combined by slip39.Combine")
+	case backendPrime:
+		return nil, errors.New("shards were created by a prime field with a custom prime, which must be provided")
+	case backendCustom:
+		return nil, errors.New("shards were created by a custom secret sharing backend, which must be provided")
+	default:
+		return nil, fmt.Errorf("secret sharing backend #%d is not supported", id)
+	}
+}
+
+type envelope struct {
+	flags     uint16
+	backend   uint8
+	digest    []byte // of the custom prime, when backend is backendPrime
+	threshold uint8
+	path      []shamir.Step
+	payload   []byte
+}
+
+func (e *envelope) MarshalBinary() ([]byte, error) {
+	if e.flags&^(envelopeFlagMask|envelopeExtendedMask) != 0 {
+		return nil, fmt.Errorf("unknown shard envelope flags: %016b", e.flags)
+	}
+	b := make([]byte, 0, 5+len(e.digest)+len(e.path)*3+len(e.payload))
+	if extended := uint8(e.flags >> 8); extended != 0 {
+		b = append(b, envelopeVersion<<5|envelopeExtended|uint8(e.flags), extended)
+	} else {
+		b = append(b, envelopeVersion<<5|uint8(e.flags))
+	}
+	if e.flags&envelopeBackend != 0 {
+		b = append(b, e.backend)
+		if e.backend == backendPrime {
+			if len(e.digest) != backendDigestSize {
+				return nil, fmt.Errorf("prime digest of %d bytes is not %d bytes long", len(e.digest), backendDigestSize)
+			}
+			b = append(b, e.digest...)
+		}
+	}
+	if e.flags&envelopeThreshold != 0 {
+		if e.threshold < 2 {
+			return nil, fmt.Errorf("shard threshold %d is less than two", e.threshold)
+		}
+		b = append(b, e.threshold)
+	}
+	if e.flags&envelopeGrouped != 0 {
+		if len(e.path) == 0 || len(e.path) > 255 {
+			return nil, fmt.Errorf("shard group depth %d is out of range [1-255]", len(e.path))
+		}
+		b = append(b, uint8(len(e.path)))
+		for _, step := range e.path {
+			b = append(b, step.Member, step.Threshold, step.Weight)
+		}
+	}
+	return append(b, e.payload...), nil
+}
+
+func (e *envelope) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return errors.New("shard is empty")
+	}
+	if version := b[0] & envelopeVersionMask >> 5; version != envelopeVersion {
+		return fmt.Errorf("shard envelope version %d is not supported", version)
+	}
+	e.flags = uint16(b[0] & envelopeFlagMask)
+	extended := b[0]&envelopeExtended != 0
+	b = b[1:]
+	if extended {
+		if len(b) < 1 {
+			return errors.New("shard extended flags are missing")
+		}
+		flags := uint16(b[0]) << 8
+		if flags&^envelopeExtendedMask != 0 {
+			return fmt.Errorf("unknown shard envelope flags: %016b", flags)
+		}
+		e.flags |= flags
+		b = b[1:]
+	}
+
+	e.backend = backendGF256
+	if e.flags&envelopeBackend != 0 {
+		if len(b) < 1 {
+			return errors.New("shard backend is missing")
+		}
+		e.backend = b[0]
+		b = b[1:]
+	}
+
+	e.digest = nil
+	if e.backend == backendPrime {
+		if len(b) < backendDigestSize {
+			return errors.New("shard prime digest is missing")
+		}
+		e.digest = b[:backendDigestSize]
+		b = b[backendDigestSize:]
+	}
+
+	e.threshold = 0
+	if e.flags&envelopeThreshold != 0 {
+		if len(b) < 1 {
+			return errors.New("shard threshold is missing")
+		}
+		if b[0] < 2 {
+			return fmt.Errorf("shard threshold %d is less than two", b[0])
+		}
+		e.threshold = b[0]
+		b = b[1:]
+	}
+
+	e.path = nil
+	if e.flags&envelopeGrouped != 0 {
+		if len(b) < 1 {
+			return errors.New("shard group is missing")
+		}
+		depth := int(b[0])
+		b = b[1:]
+		if depth == 0 || len(b) < depth*3 {
+			return errors.New("shard group is corrupt")
+		}
+		e.path = make([]shamir.Step, depth)
+		for i := range e.path {
+			e.path[i] = shamir.Step{
+				Member:    b[i*3],
+				Threshold: b[i*3+1],
+				Weight:    b[i*3+2],
+			}
+		}
+		b = b[depth*3:]
+	}
+	e.payload = b
+	return nil
+}
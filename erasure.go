@@ -0,0 +1,58 @@
+package kidwords
+
+import (
+	"errors"
+	"io"
+)
+
+// isPlaceholder reports whether the rune belongs to a placeholder, like "?" or "____", which stands for a word that cannot be read.
+func isPlaceholder(rn rune) bool {
+	return rn == '?' || rn == '_'
+}
+
+// erase consumes the rest of a placeholder and records its position.
+func (r *Reader) erase() (byte, error) {
+	r.column++
+	for {
+		rn, _, err := r.r.ReadRune()
+		if err != nil {
+			if err != io.EOF {
+				return 0, err
+			}
+			break
+		}
+		if !isPlaceholder(rn) {
+			_ = r.r.UnreadRune()
+			break
+		}
+		r.column++
+	}
+	r.erasures = append(r.erasures, r.index-r.skipped)
+	r.index++
+	return 0, nil
+}
+
+// Erasures returns the positions of decoded bytes that were replaced by placeholders, when [WithErasures] option is set. Erased bytes are decoded as zeros.
+func (r *Reader) Erasures() []int {
+	return r.erasures
+}
+
+type erasuresOption struct{}
+
+func (erasuresOption) applyReaderOption(o *readerOptions) error {
+	if o.erasures {
+		return errors.New("erasures are already set")
+	}
+	o.erasures = true
+	return nil
+}
+
+func (e erasuresOption) applyCombineOption(o *combineOptions) error {
+	o.reader = append(o.reader, e)
+	return nil
+}
+
+// WithErasures accepts placeholders, like "?" or "____", for words that cannot be read from torn or faded paper. Each run of question marks and underscores stands for one word. [Reader.Erasures] reports their positions, so that [Combine] can recover the erased bytes of a shard from the other shards.
+func WithErasures() ReaderOption {
+	return erasuresOption{}
+}
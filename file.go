@@ -0,0 +1,251 @@
+package kidwords
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Encrypted files start with a header made of magic bytes, format version, and the size of plain text chunks. The header is authenticated together with every chunk. Each chunk is sealed with AES-256-GCM using a nonce built from the chunk counter and a flag that marks the final chunk, so that chunks cannot be reordered, dropped, or truncated without detection.
+const (
+	fileMagic      = "kidwords"
+	fileVersion    = 1
+	fileHeaderSize = len(fileMagic) + 1 + 4
+	fileKeySize    = 32
+	fileChunkSize  = 64 * 1024
+)
+
+// SplitFile encrypts the contents of [io.Reader] into [io.Writer] under a random key and splits the key into Kid Words shards. The file can be restored by [CombineFile] once a quorum of shards is gathered. Secrets that are too long to be written down as shards, like password manager exports, can be protected this way.
+func SplitFile(
+	w io.Writer,
+	r io.Reader,
+	total,
+	quorum int,
+	withOptions ...SplitOption,
+) (shards Shards, err error) {
+	return SplitFileContext(context.Background(), w, r, total, quorum, withOptions...)
+}
+
+// SplitFileContext works like [SplitFile], but stops between file chunks when the context is done. The partially written file should be discarded then.
+func SplitFileContext(
+	ctx context.Context,
+	w io.Writer,
+	r io.Reader,
+	total,
+	quorum int,
+	withOptions ...SplitOption,
+) (shards Shards, err error) {
+	key := make(Secret, fileKeySize)
+	defer key.Wipe()
+	if _, err = rand.Read(key); err != nil {
+		return nil, err
+	}
+	if shards, err = SplitBytesContext(ctx, key, total, quorum, withOptions...); err != nil {
+		return nil, err
+	}
+	if err = encryptFile(ctx, w, r, key); err != nil {
+		return nil, err
+	}
+	return shards, nil
+}
+
+// CombineFile recovers the key from a quorum of shards created by [SplitFile] and decrypts the file from [io.Reader] into [io.Writer].
+func CombineFile(
+	w io.Writer,
+	r io.Reader,
+	shards []string,
+	withOptions ...CombineOption,
+) error {
+	return CombineFileContext(context.Background(), w, r, shards, withOptions...)
+}
+
+// CombineFileContext works like [CombineFile], but stops between file chunks when the context is done. The partially decrypted file should be discarded then.
+func CombineFileContext(
+	ctx context.Context,
+	w io.Writer,
+	r io.Reader,
+	shards []string,
+	withOptions ...CombineOption,
+) error {
+	key, err := combineWords(ctx, shards, withOptions)
+	if err != nil {
+		return err
+	}
+	defer Secret(key).Wipe()
+	return decryptFile(ctx, w, r, key)
+}
+
+// CombineFileErasures works like [CombineFile], but takes shards that were already decoded from Kid Words with the positions of their erased bytes, like [CombineErasures].
+func CombineFileErasures(
+	w io.Writer,
+	r io.Reader,
+	shards [][]byte,
+	erasures [][]int,
+	withOptions ...CombineOption,
+) error {
+	return CombineFileErasuresContext(context.Background(), w, r, shards, erasures, withOptions...)
+}
+
+// CombineFileErasuresContext works like [CombineFileErasures], but stops between file chunks when the context is done.
+func CombineFileErasuresContext(
+	ctx context.Context,
+	w io.Writer,
+	r io.Reader,
+	shards [][]byte,
+	erasures [][]int,
+	withOptions ...CombineOption,
+) error {
+	key, err := CombineErasuresContext(ctx, shards, erasures, withOptions...)
+	if err != nil {
+		return err
+	}
+	defer Secret(key).Wipe()
+	return decryptFile(ctx, w, r, key)
+}
+
+func newFileCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != fileKeySize {
+		return nil, fmt.Errorf("file key must be %d bytes long", fileKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// fileNonce encodes chunk counter in the first eleven bytes and the final chunk flag in the last byte.
+func fileNonce(nonce []byte, counter uint64, final bool) ([]byte, error) {
+	if counter >= 1<<56 {
+		return nil, errors.New("file is too large")
+	}
+	for i := range nonce {
+		nonce[i] = 0
+	}
+	binary.BigEndian.PutUint64(nonce[3:11], counter)
+	if final {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce, nil
+}
+
+func encryptFile(ctx context.Context, w io.Writer, r io.Reader, key []byte) (err error) {
+	aead, err := newFileCipher(key)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, 0, fileHeaderSize)
+	header = append(header, fileMagic...)
+	header = append(header, fileVersion)
+	header = binary.BigEndian.AppendUint32(header, fileChunkSize)
+	if _, err = w.Write(header); err != nil {
+		return err
+	}
+
+	var (
+		buffered = bufio.NewReader(r)
+		nonce    = make([]byte, aead.NonceSize())
+		chunk    = make([]byte, fileChunkSize, fileChunkSize+aead.Overhead())
+	)
+	for counter := uint64(0); ; counter++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		n, err := io.ReadFull(buffered, chunk)
+		final := false
+		switch err {
+		case nil:
+			// look ahead to find out whether this chunk is the last one
+			if _, err = buffered.Peek(1); err == io.EOF {
+				final = true
+			} else if err != nil {
+				return err
+			}
+		case io.EOF, io.ErrUnexpectedEOF:
+			final = true
+		default:
+			return err
+		}
+
+		if nonce, err = fileNonce(nonce, counter, final); err != nil {
+			return err
+		}
+		if _, err = w.Write(aead.Seal(chunk[:0], nonce, chunk[:n], header)); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+func decryptFile(ctx context.Context, w io.Writer, r io.Reader, key []byte) (err error) {
+	aead, err := newFileCipher(key)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, fileHeaderSize)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("cannot read file header: %w", err)
+	}
+	if !bytes.Equal(header[:len(fileMagic)], []byte(fileMagic)) {
+		return errors.New("file was not encrypted by Kid Words")
+	}
+	if version := header[len(fileMagic)]; version != fileVersion {
+		return fmt.Errorf("file version %d is not supported", version)
+	}
+	chunkSize := binary.BigEndian.Uint32(header[len(fileMagic)+1:])
+	if chunkSize == 0 || chunkSize > 16*1024*1024 {
+		return fmt.Errorf("file chunk size %d is out of range", chunkSize)
+	}
+
+	var (
+		buffered = bufio.NewReader(r)
+		nonce    = make([]byte, aead.NonceSize())
+		chunk    = make([]byte, int(chunkSize)+aead.Overhead())
+	)
+	for counter := uint64(0); ; counter++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		n, err := io.ReadFull(buffered, chunk)
+		final := false
+		switch err {
+		case nil:
+			if _, err = buffered.Peek(1); err == io.EOF {
+				final = true
+			} else if err != nil {
+				return err
+			}
+		case io.ErrUnexpectedEOF:
+			final = true
+		case io.EOF:
+			return errors.New("file is truncated")
+		default:
+			return err
+		}
+
+		if nonce, err = fileNonce(nonce, counter, final); err != nil {
+			return err
+		}
+		plain, err := aead.Open(chunk[:0], nonce, chunk[:n], header)
+		if err != nil {
+			return fmt.Errorf("cannot decrypt file chunk %d: %w", counter+1, err)
+		}
+		if _, err = w.Write(plain); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package kidwords
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestSplitFile(t *testing.T) {
+	for _, size := range []int{0, 1, fileChunkSize - 1, fileChunkSize, fileChunkSize*2 + 7} {
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+
+		encrypted := &bytes.Buffer{}
+		shards, err := SplitFile(encrypted, bytes.NewReader(data), 5, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(encrypted.Bytes(), data) && size > 8 { // a byte or two may show up in the ciphertext by chance
+			t.Fatal("file was not encrypted")
+		}
+
+		decrypted := &bytes.Buffer{}
+		if err = CombineFile(decrypted, bytes.NewReader(encrypted.Bytes()), shards[1:4]); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted.Bytes(), data) {
+			t.Fatalf("decrypted file of %d bytes does not match", size)
+		}
+
+		decoded := make([][]byte, 3)
+		for i, shard := range shards[:3] {
+			if decoded[i], err = ToBytes(shard); err != nil {
+				t.Fatal(err)
+			}
+		}
+		decrypted.Reset()
+		if err = CombineFileErasures(decrypted, bytes.NewReader(encrypted.Bytes()), decoded, make([][]int, 3)); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted.Bytes(), data) {
+			t.Fatalf("file of %d bytes decrypted from decoded shards does not match", size)
+		}
+
+		if size > fileChunkSize {
+			truncated := encrypted.Bytes()[:fileHeaderSize+fileChunkSize+16]
+			if err = CombineFile(&bytes.Buffer{}, bytes.NewReader(truncated), shards[:3]); err == nil {
+				t.Fatal("truncated file was decrypted")
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package kidwords
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+const (
+	maxPassphraseEntropy = 1024
+	passphraseDigits     = "0123456789"
+	// passphraseSymbols leave out quotes, slashes and spaces, which some sites and shells mishandle.
+	passphraseSymbols = "!#$%&*+-=?@^_"
+)
+
+// PassphraseCharacters are appended to the last word of a generated passphrase for sites that demand digits or symbols. The zero value appends nothing.
+type PassphraseCharacters struct {
+	Digits  int
+	Symbols int // punctuation, appended after the digits
+}
+
+// Validate checks that the counts are usable.
+func (c PassphraseCharacters) Validate() error {
+	if c.Digits < 0 {
+		return errors.New("number of digits cannot be negative")
+	}
+	if c.Symbols < 0 {
+		return errors.New("number of symbols cannot be negative")
+	}
+	return nil
+}
+
+// Passphrase is a random password made of dictionary words.
+type Passphrase struct {
+	// Phrase should be wiped once it is no longer needed.
+	Phrase Secret
+	// Entropy is the number of bits an attacker must guess, assuming that the dictionary and the options are known.
+	Entropy float64
+}
+
+// GeneratePassphrase draws enough random words to reach the given bits of entropy. Every word of a dictionary carries eight bits, so the entropy is rounded up to the whole word. Check words added by [WithLayout] do not add entropy. The extra characters are appended to the last word.
+func GeneratePassphrase(bits int, extra PassphraseCharacters, withOptions ...WriterOption) (p Passphrase, err error) {
+	if bits < 1 || bits > maxPassphraseEntropy {
+		return p, fmt.Errorf("passphrase entropy %d bits is out of range [1-%d]", bits, maxPassphraseEntropy)
+	}
+	if err = extra.Validate(); err != nil {
+		return p, err
+	}
+	o, err := newWriterOptions(withOptions)
+	if err != nil {
+		return p, err
+	}
+	if o.compress {
+		return p, errors.New("passphrases cannot be compressed")
+	}
+	if o.kindSet {
+		return p, errors.New("passphrases are not framed in a container")
+	}
+
+	random := make(Secret, (bits+7)/8)
+	defer random.Wipe()
+	if _, err = rand.Read(random); err != nil {
+		return p, err
+	}
+	b := &bytes.Buffer{}
+	b.Grow(len(random)*16 + extra.Digits + extra.Symbols) // avoid copies of the phrase left behind by growing
+	w := newWriter(b, o)
+	if separator := w.separator; separator != nil {
+		first := true
+		w.separator = func() []byte { // the phrase should not start with a separator
+			if first {
+				first = false
+				return nil
+			}
+			return separator()
+		}
+	}
+	if _, err = w.Write(random); err != nil {
+		return p, err
+	}
+	if err = w.Close(); err != nil {
+		return p, err
+	}
+	p.Phrase = Secret(b.Bytes())
+	p.Entropy = float64(len(random) * 8)
+
+	for _, characters := range []struct {
+		Set   string
+		Count int
+	}{
+		{Set: passphraseDigits, Count: extra.Digits},
+		{Set: passphraseSymbols, Count: extra.Symbols},
+	} {
+		for i := 0; i < characters.Count; i++ {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(characters.Set))))
+			if err != nil {
+				p.Phrase.Wipe()
+				return Passphrase{}, err
+			}
+			p.Phrase = append(p.Phrase, characters.Set[n.Int64()])
+		}
+		p.Entropy += float64(characters.Count) * math.Log2(float64(len(characters.Set)))
+	}
+	return p, nil
+}
@@ -0,0 +1,46 @@
+package kidwords
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassphrase(t *testing.T) {
+	p, err := GeneratePassphrase(60, PassphraseCharacters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Entropy != 64 {
+		t.Fatalf("entropy %.1f bits was not rounded up to the whole word", p.Entropy)
+	}
+	decoded, err := ToBytes(string(p.Phrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 8 {
+		t.Fatalf("passphrase %q has %d words instead of 8", string(p.Phrase), len(decoded))
+	}
+
+	p, err = GeneratePassphrase(16, PassphraseCharacters{Digits: 2, Symbols: 1}, WithSeparator(func() []byte { return []byte(".") }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := strings.Split(string(p.Phrase), ".") // not one of passphraseSymbols
+	last := words[len(words)-1]
+	if len(words) != 2 || !strings.ContainsAny(last[len(last)-3:len(last)-1], passphraseDigits) || !strings.ContainsAny(last[len(last)-1:], passphraseSymbols) {
+		t.Fatalf("passphrase %q does not end with two digits and a symbol", string(p.Phrase))
+	}
+	if p.Entropy < 16+6.6+3.7 || p.Entropy > 16+6.7+3.8 {
+		t.Fatalf("digits and symbols added %.2f bits of entropy", p.Entropy-16)
+	}
+
+	if _, err = GeneratePassphrase(64, PassphraseCharacters{}, WithCompression()); err == nil {
+		t.Fatal("compression was accepted")
+	}
+	if _, err = GeneratePassphrase(0, PassphraseCharacters{}); err == nil {
+		t.Fatal("zero entropy was accepted")
+	}
+	if _, err = GeneratePassphrase(64, PassphraseCharacters{Digits: -1}); err == nil {
+		t.Fatal("negative number of digits was accepted")
+	}
+}
@@ -1,3 +1,10 @@
 module github.com/dkotik/kidwords
 
 go 1.21.0
+
+require (
+	golang.org/x/crypto v0.14.0
+	golang.org/x/text v0.13.0
+)
+
+require golang.org/x/sys v0.13.0 // indirect
@@ -0,0 +1,102 @@
+package kidwords
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+)
+
+// Numbers are encoded in big-endian order without leading zeros, so small numbers take few words, followed by a short checksum. The checksum covers a tag of the number type, so an identifier cannot be mistaken for a number of another type. Every number carries two checksum words, so a mistyped word goes unnoticed only once in 65536 times.
+const (
+	numberTagInteger   = "integer"
+	numberTagUUID      = "uuid"
+	numberChecksumSize = 2
+)
+
+func numberChecksum(tag string, b []byte) []byte {
+	h := crc32.New(ChecksumTable)
+	_, _ = h.Write([]byte(tag))
+	_, _ = h.Write(b)
+	return h.Sum(nil)[:numberChecksumSize]
+}
+
+func fromNumber(tag string, b []byte, withOptions []WriterOption) (string, error) {
+	return FromBytes(append(b, numberChecksum(tag, b)...), withOptions...)
+}
+
+func toNumber(tag, s string, withOptions []ReaderOption) ([]byte, error) {
+	b, err := ToBytes(s, withOptions...)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < numberChecksumSize {
+		return nil, errors.New("number is missing")
+	}
+	value, checksum := b[:len(b)-numberChecksumSize], b[len(b)-numberChecksumSize:]
+	if expected := numberChecksum(tag, value); string(expected) != string(checksum) {
+		return nil, errors.New("number checksum does not match")
+	}
+	return value, nil
+}
+
+// FromInt encodes a non-negative integer, like an account number or a PIN, into as few Kid Words as possible followed by two checksum words. Use [ToInt] to decode it.
+func FromInt(n int64, withOptions ...WriterOption) (string, error) {
+	if n < 0 {
+		return "", errors.New("negative integers cannot be encoded")
+	}
+	return FromBigInt(big.NewInt(n), withOptions...)
+}
+
+// ToInt decodes an integer encoded by [FromInt] or [FromBigInt] and verifies its checksum.
+func ToInt(s string, withOptions ...ReaderOption) (int64, error) {
+	n, err := ToBigInt(s, withOptions...)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("integer %s overflows int64", n)
+	}
+	return n.Int64(), nil
+}
+
+// FromBigInt encodes a non-negative integer of any size into Kid Words followed by a checksum. Use [ToBigInt] to decode it.
+func FromBigInt(n *big.Int, withOptions ...WriterOption) (string, error) {
+	if n == nil {
+		return "", errors.New("cannot encode a <nil> integer")
+	}
+	if n.Sign() < 0 {
+		return "", errors.New("negative integers cannot be encoded")
+	}
+	return fromNumber(numberTagInteger, n.Bytes(), withOptions)
+}
+
+// ToBigInt decodes an integer encoded by [FromBigInt] or [FromInt] and verifies its checksum.
+func ToBigInt(s string, withOptions ...ReaderOption) (*big.Int, error) {
+	b, err := toNumber(numberTagInteger, s, withOptions)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 0 && b[0] == 0 {
+		return nil, errors.New("integer has leading zeros")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// FromUUID encodes a UUID into eighteen Kid Words: sixteen for the identifier and two for the checksum. The array type matches popular UUID packages, like github.com/google/uuid.
+func FromUUID(id [16]byte, withOptions ...WriterOption) (string, error) {
+	return fromNumber(numberTagUUID, id[:], withOptions)
+}
+
+// ToUUID decodes a UUID encoded by [FromUUID] and verifies its checksum.
+func ToUUID(s string, withOptions ...ReaderOption) (id [16]byte, err error) {
+	b, err := toNumber(numberTagUUID, s, withOptions)
+	if err != nil {
+		return id, err
+	}
+	if len(b) != len(id) {
+		return id, fmt.Errorf("UUID must be %d bytes long, but %d were decoded", len(id), len(b))
+	}
+	copy(id[:], b)
+	return id, nil
+}
@@ -33,6 +33,7 @@ package kidwords
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"strings"
 )
@@ -42,12 +43,20 @@ import (
 
 // FromReader translates [io.Reader] stream into Kid Words.
 func FromReader(r io.Reader, withOptions ...WriterOption) (string, error) {
+	return FromReaderContext(context.Background(), r, withOptions...)
+}
+
+// FromReaderContext works like [FromReader], but stops reading when the context is done.
+func FromReaderContext(ctx context.Context, r io.Reader, withOptions ...WriterOption) (string, error) {
 	b := bytes.Buffer{}
 	w, err := NewWriter(&b, withOptions...)
 	if err != nil {
 		return "", err
 	}
-	if _, err = io.Copy(w, r); err != nil {
+	if _, err = io.Copy(w, contextReader{ctx: ctx, r: r}); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
 		return "", err
 	}
 	return b.String(), nil
@@ -63,33 +72,34 @@ func FromString(s string, withOptions ...WriterOption) (string, error) {
 	return FromReader(strings.NewReader(s), withOptions...)
 }
 
-// func FromInt(r io.Reader) (n int64, err error) {
-// 	wrapped := NewReader(r)
-// 	b := &bytes.Buffer{}
-// 	_, err = io.CopyN(b, wrapped, 24)
-// 	if err != nil && err != io.EOF {
-// 		return 0, err
-// 	}
-//
-// 	chopped, ok := ChecksumChop(b.Bytes())
-// 	if !ok {
-// 		return 0, errors.New("checksum did not match")
-// 	}
-//
-// 	x := new(big.Int).SetBytes(chopped)
-// 	return x.Int64(), nil
-// }
-
 // ToWriter streams translated Kid Words into [io.Writer].
 func ToWriter(w io.Writer, s string, withOptions ...ReaderOption) error {
+	return ToWriterContext(context.Background(), w, s, withOptions...)
+}
+
+// ToWriterContext works like [ToWriter], but stops writing when the context is done.
+func ToWriterContext(ctx context.Context, w io.Writer, s string, withOptions ...ReaderOption) error {
 	r, err := NewReader(strings.NewReader(s), withOptions...)
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(w, r)
+	_, err = io.Copy(w, contextReader{ctx: ctx, r: r})
 	return err
 }
 
+// contextReader fails once the context is done, which interrupts [io.Copy] between chunks.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
 // ToBytes translates Kid Words into bytes.
 func ToBytes(s string, withOptions ...ReaderOption) ([]byte, error) {
 	b := bytes.Buffer{}
@@ -1,28 +1,95 @@
 package kidwords
 
-import "fmt"
-
-// func TestIntTransformations(t *testing.T) {
-// 	cases := []int64{0, 9, 16, 32, 999999, 38729387428974, 2374761653249823, 88999999}
-// 	for _, i := range cases {
-// 		t.Run(fmt.Sprintf("transforming integer: %d", i), func(t *testing.T) {
-// 			b := &bytes.Buffer{}
-// 			if err := WriteInt(b, i); err != nil {
-// 				t.Fatal(err)
-// 			}
-// 			legitWords(t, b.String())
-// 			t.Log("words:", b.String())
-//
-// 			j, err := ReadInt(bytes.NewReader(b.Bytes()))
-// 			if err != nil {
-// 				t.Fatal(err)
-// 			}
-// 			if j != i {
-// 				t.Fatalf("%d does not match %d", j, i)
-// 			}
-// 		})
-// 	}
-// }
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestIntTransformations(t *testing.T) {
+	cases := []int64{0, 9, 16, 32, 999999, 38729387428974, 2374761653249823, 88999999, math.MaxInt64}
+	for _, i := range cases {
+		t.Run(fmt.Sprintf("transforming integer: %d", i), func(t *testing.T) {
+			words, err := FromInt(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Log("words:", words)
+			if expected := len(big.NewInt(i).Bytes()) + numberChecksumSize; len(strings.Fields(words)) != expected {
+				t.Fatalf("%d takes %d words instead of %d", i, len(strings.Fields(words)), expected)
+			}
+
+			j, err := ToInt(words)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if j != i {
+				t.Fatalf("%d does not match %d", j, i)
+			}
+		})
+	}
+
+	if _, err := FromInt(-1); err == nil {
+		t.Fatal("negative integer was encoded")
+	}
+	words, err := FromInt(4421)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(words)
+	fields[0], fields[1] = fields[1], fields[0]
+	if _, err = ToInt(strings.Join(fields, " ")); err == nil {
+		t.Fatal("swapped words were decoded")
+	}
+}
+
+func TestBigIntTransformations(t *testing.T) {
+	n, ok := new(big.Int).SetString("340282366920938463463374607431768211457", 10)
+	if !ok {
+		t.Fatal("cannot parse integer")
+	}
+	words, err := FromBigInt(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ToBigInt(words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Cmp(n) != 0 {
+		t.Fatalf("%s does not match %s", decoded, n)
+	}
+	if _, err = ToInt(words); err == nil {
+		t.Fatal("integer overflow was not detected")
+	}
+}
+
+func TestUUIDTransformations(t *testing.T) {
+	id := [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	words, err := FromUUID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(strings.Fields(words)); n != 18 {
+		t.Fatalf("UUID takes %d words", n)
+	}
+	decoded, err := ToUUID(words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != id {
+		t.Fatalf("%x does not match %x", decoded, id)
+	}
+	if _, err = ToBigInt(words); err == nil {
+		t.Fatal("UUID was decoded as an integer")
+	}
+}
 
 func ExampleFromBytes() {
 	fmt.Println(
@@ -36,3 +103,36 @@ func ExampleToBytes() {
 	fmt.Println(string(b), err)
 	// Output: marvel <nil>
 }
+
+func ExampleFromInt() {
+	words, err := FromInt(4421)
+	if err != nil {
+		panic(err)
+	}
+	n, err := ToInt(words)
+	fmt.Println(len(strings.Fields(words)), n, err)
+	// Output: 4 4421 <nil>
+}
+
+func TestContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := FromReaderContext(ctx, strings.NewReader("marvel")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if err := ToWriterContext(ctx, io.Discard, "hole gold hush"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if _, err := SplitFileContext(ctx, io.Discard, strings.NewReader("file"), 3, 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+
+	encrypted := &bytes.Buffer{}
+	shards, err := SplitFile(encrypted, strings.NewReader("file"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = CombineFileContext(ctx, io.Discard, encrypted, shards[:2]); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package kidwords
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+)
+
+// Layout arranges words for transcription on paper. The zero value writes all words on one line separated by spaces. Three words in a group, two groups per line, numbers and check words look like:
+//
+//	$ printf marvelous | kidwords encode --group 3 --line 2 --numbered --checksum -
+//	1. hole gold hush  item half hint = flow
+//	2. hook iron icon = rose
+type Layout struct {
+	// GroupSize is the number of words in a group. Groups are separated by two spaces.
+	GroupSize int
+	// GroupsPerLine is the number of groups on a line. Zero puts all words on one line.
+	GroupsPerLine int
+	// Numbered prefixes each line with its number.
+	Numbered bool
+	// Checksum ends each line with a check word computed over the line number and the words of the line, so that a mistyped word or a skipped line is found by re-reading one line.
+	Checksum bool
+}
+
+// Validate checks that the layout is usable.
+func (l Layout) Validate() error {
+	if l.GroupSize < 0 {
+		return errors.New("group size cannot be negative")
+	}
+	if l.GroupsPerLine < 0 {
+		return errors.New("groups per line cannot be negative")
+	}
+	if l.GroupsPerLine > 0 && l.GroupSize == 0 {
+		return errors.New("groups per line require a group size")
+	}
+	return nil
+}
+
+// wordsPerLine returns zero when all words are on one line.
+func (l Layout) wordsPerLine() int {
+	return l.GroupSize * l.GroupsPerLine
+}
+
+// lineChecksum returns the check word of a line, which is counted from zero.
+func lineChecksum(line int, data []byte) byte {
+	h := crc32.New(ChecksumTable)
+	_, _ = h.Write(binary.AppendUvarint(nil, uint64(line)))
+	_, _ = h.Write(data)
+	return h.Sum(nil)[0]
+}
+
+// appendLayoutWord appends the separator that precedes the next word and the word itself.
+func (w *Writer) appendLayoutWord(b []byte, c byte) []byte {
+	l := w.layout
+	switch {
+	case len(w.line) > 0 && l.GroupSize > 0 && len(w.line)%l.GroupSize == 0:
+		b = append(b, "  "...)
+	case len(w.line) > 0:
+		b = append(b, ' ')
+	case w.lines > 0:
+		b = append(b, '\n')
+	}
+	if len(w.line) == 0 {
+		w.lines++
+		if l.Numbered {
+			b = strconv.AppendInt(b, int64(w.lines), 10)
+			b = append(b, ". "...)
+		}
+	}
+	b = append(b, w.dictionary[c]...)
+	w.line = append(w.line, c)
+	if perLine := l.wordsPerLine(); perLine > 0 && len(w.line) == perLine {
+		b = w.appendLineEnd(b)
+	}
+	return b
+}
+
+// appendLineEnd appends the check word, when required, and starts a new line.
+func (w *Writer) appendLineEnd(b []byte) []byte {
+	if w.layout.Checksum {
+		b = append(b, " = "...)
+		b = append(b, w.dictionary[lineChecksum(w.lines-1, w.line)]...)
+	}
+	w.line = w.line[:0]
+	return b
+}
+
+type layoutOption Layout
+
+func (l layoutOption) applyWriterOption(o *writerOptions) error {
+	if o.layout != nil {
+		return errors.New("layout is already set")
+	}
+	if o.separator != nil {
+		return errors.New("layout cannot be combined with a separator function")
+	}
+	layout := Layout(l)
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+	o.layout = &layout
+	return nil
+}
+
+func (l layoutOption) applySplitOption(o *splitOptions) error {
+	o.writer = append(o.writer, l)
+	return nil
+}
+
+func (l layoutOption) applyReaderOption(o *readerOptions) error {
+	if o.layout != nil {
+		return errors.New("layout is already set")
+	}
+	layout := Layout(l)
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+	o.layout = &layout
+	return nil
+}
+
+func (l layoutOption) applyCombineOption(o *combineOptions) error {
+	o.reader = append(o.reader, l)
+	return nil
+}
+
+// WithLayout arranges words into groups and numbered lines with optional check words. The [Writer] must be closed to complete the last line. The [Reader] needs the same layout to verify and remove check words, but it ignores line breaks and numbers, so the words may be typed in any arrangement.
+func WithLayout(l Layout) Option {
+	return layoutOption(l)
+}
+
+// LineChecksumError reports a line of a [Layout] whose words do not match its check word.
+type LineChecksumError struct {
+	// Line is the line number as printed, starting at 1.
+	Line int
+	// Index counts the words, including check words, that precede the line.
+	Index int
+}
+
+func (e *LineChecksumError) Error() string {
+	return fmt.Sprintf("words on line %d do not match the check word", e.Line)
+}
+
+// nextLine decodes the words of the next line and verifies them against the check word that ends the line.
+func (r *Reader) nextLine() error {
+	perLine := r.layout.wordsPerLine()
+	start := r.index
+	erased := len(r.erasures)
+	r.pending = r.pending[:0]
+	for perLine == 0 || len(r.pending) <= perLine {
+		c, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		r.pending = append(r.pending, c)
+	}
+	if len(r.pending) == 0 {
+		return io.EOF
+	}
+	data, check := r.pending[:len(r.pending)-1], r.pending[len(r.pending)-1]
+	if len(r.erasures) > erased && r.erasures[len(r.erasures)-1] == r.index-r.skipped-1 {
+		r.erasures = r.erasures[:len(r.erasures)-1] // check word is not data
+		erased = -1
+	}
+	r.skipped++
+	if len(data) == 0 {
+		return &LineChecksumError{Line: r.lines + 1, Index: start}
+	}
+	// lines with erased words cannot be verified
+	if erased == len(r.erasures) && lineChecksum(r.lines, data) != check {
+		return &LineChecksumError{Line: r.lines + 1, Index: start}
+	}
+	r.lines++
+	r.ready = data
+	return nil
+}
+
+func (r *Reader) readLines(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(r.ready) == 0 {
+			if r.err != nil {
+				break
+			}
+			r.err = r.nextLine()
+			continue
+		}
+		copied := copy(p[n:], r.ready)
+		r.ready = r.ready[copied:]
+		n += copied
+	}
+	if n > 0 {
+		return n, nil
+	}
+	return 0, r.err
+}
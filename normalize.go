@@ -0,0 +1,117 @@
+package kidwords
+
+import (
+	"errors"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeWord brings a word into the form used for matching regardless of case and Unicode composition. When diacritics are stripped, "Café" and "cafe" match.
+func normalizeWord(word string, stripDiacritics bool) string {
+	chain := []transform.Transformer{norm.NFKD}
+	if stripDiacritics {
+		chain = append(chain, runes.Remove(runes.In(unicode.Mn)))
+	}
+	chain = append(chain, cases.Fold(), norm.NFC)
+	normalized, _, err := transform.String(transform.Chain(chain...), word)
+	if err != nil {
+		return word
+	}
+	return normalized
+}
+
+// wordMatcher finds dictionary words typed with different case, Unicode composition, or diacritics, and splits words typed together.
+type wordMatcher struct {
+	exact           map[string]byte
+	normalized      map[string]byte
+	maxWordLen      int // in bytes of normalized words
+	stripDiacritics bool
+}
+
+// newWordMatcher leaves out of normalized matching the words that become the same after normalization, like "Rose" and "rose", because they could no longer be told apart. Such words only match exactly.
+func newWordMatcher(reverse map[string]byte, stripDiacritics bool) *wordMatcher {
+	m := &wordMatcher{
+		exact:           reverse,
+		normalized:      make(map[string]byte, len(reverse)),
+		stripDiacritics: stripDiacritics,
+	}
+	ambiguous := make(map[string]struct{})
+	for word, b := range reverse {
+		key := normalizeWord(word, stripDiacritics)
+		if _, ok := m.normalized[key]; ok {
+			ambiguous[key] = struct{}{}
+			continue
+		}
+		m.normalized[key] = b
+	}
+	for key := range ambiguous {
+		delete(m.normalized, key)
+	}
+	for key := range m.normalized {
+		m.maxWordLen = max(m.maxWordLen, len(key))
+	}
+	return m
+}
+
+// match appends the bytes of a word to b. Exact matches do not allocate.
+func (m *wordMatcher) match(b, word []byte) ([]byte, bool) {
+	if c, ok := m.exact[string(word)]; ok {
+		return append(b, c), true
+	}
+	normalized := normalizeWord(string(word), m.stripDiacritics)
+	if c, ok := m.normalized[normalized]; ok {
+		return append(b, c), true
+	}
+	return m.segment(b, normalized)
+}
+
+// segment splits words typed together, like "farmline", when there is exactly one way to do it.
+func (m *wordMatcher) segment(b []byte, word string) ([]byte, bool) {
+	// ways[i] counts segmentations of word[i:], up to two
+	ways := make([]uint8, len(word)+1)
+	ways[len(word)] = 1
+	for i := len(word) - 1; i >= 0; i-- {
+		for j := i + 1; j <= len(word) && j-i <= m.maxWordLen; j++ {
+			if _, ok := m.normalized[word[i:j]]; ok && ways[j] > 0 {
+				ways[i] = min(ways[i]+ways[j], 2)
+			}
+		}
+	}
+	if ways[0] != 1 {
+		return b, false // not words or ambiguous
+	}
+	for i := 0; i < len(word); {
+		for j := min(len(word), i+m.maxWordLen); j > i; j-- {
+			if c, ok := m.normalized[word[i:j]]; ok && ways[j] > 0 {
+				b = append(b, c)
+				i = j
+				break
+			}
+		}
+	}
+	return b, true
+}
+
+type diacriticFoldingOption struct{}
+
+func (diacriticFoldingOption) applyReaderOption(o *readerOptions) error {
+	if o.stripDiacritics {
+		return errors.New("diacritic folding is already set")
+	}
+	o.stripDiacritics = true
+	return nil
+}
+
+func (d diacriticFoldingOption) applyCombineOption(o *combineOptions) error {
+	o.reader = append(o.reader, d)
+	return nil
+}
+
+// WithDiacriticFolding matches words typed without accents, like "nino" for "niño", for dictionaries that contain diacritics. Dictionary words that differ only by their diacritics are matched exactly.
+func WithDiacriticFolding() ReaderOption {
+	return diacriticFoldingOption{}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/dkotik/kidwords/dictionary"
+	"github.com/dkotik/kidwords/shamir"
 )
 
 // type SplitFunc func()
@@ -13,21 +14,65 @@ type SeparatorFunc func() []byte
 type writerOptions struct {
 	separator  SeparatorFunc
 	dictionary *dictionary.Dictionary
+	layout     *Layout
+	compress   bool
+	kind       PayloadKind
+	kindSet    bool
 }
 
 type WriterOption interface {
+	SplitOption
 	applyWriterOption(*writerOptions) error
 }
 
+type splitOptions struct {
+	sequential bool
+	backend    shamir.Backend
+	passphrase Secret
+	argon      *ArgonParameters
+	decoy      *sealSlot
+	deniable   bool
+	timeLock   *TimeLock
+	writer     []WriterOption
+}
+
+// SplitOption configures [Split] and [SplitPolicy]. Every [WriterOption] is also a SplitOption that is applied to shard encoding.
+type SplitOption interface {
+	applySplitOption(*splitOptions) error
+}
+
 type readerOptions struct {
 	// split SplitFunc
-	dictionary map[string]byte
+	dictionary      map[string]byte
+	layout          *Layout
+	stripDiacritics bool
+	erasures        bool
 }
 
 type ReaderOption interface {
+	CombineOption
 	applyReaderOption(*readerOptions) error
 }
 
+type combineOptions struct {
+	backend    shamir.Backend
+	passphrase Secret
+	progress   func(done, total uint64)
+	legacy     bool
+	reader     []ReaderOption
+}
+
+// CombineOption configures [Combine]. Every [ReaderOption] is also a CombineOption that is applied to shard decoding.
+type CombineOption interface {
+	applyCombineOption(*combineOptions) error
+}
+
+// ShardOption configures both [Split] and [Combine].
+type ShardOption interface {
+	SplitOption
+	CombineOption
+}
+
 type Option interface {
 	ReaderOption
 	WriterOption
@@ -66,6 +111,16 @@ func (d *dictionaryOption) applyReaderOption(o *readerOptions) error {
 	return nil
 }
 
+func (d *dictionaryOption) applySplitOption(o *splitOptions) error {
+	o.writer = append(o.writer, d)
+	return nil
+}
+
+func (d *dictionaryOption) applyCombineOption(o *combineOptions) error {
+	o.reader = append(o.reader, d)
+	return nil
+}
+
 func WithDictionary(d *dictionary.Dictionary) Option {
 	return &dictionaryOption{dictionary: d}
 }
@@ -94,6 +149,16 @@ func (d dictionaryFileOption) applyReaderOption(o *readerOptions) error {
 	return (&dictionaryOption{dictionary: &dictionary}).applyReaderOption(o)
 }
 
+func (d dictionaryFileOption) applySplitOption(o *splitOptions) error {
+	o.writer = append(o.writer, d)
+	return nil
+}
+
+func (d dictionaryFileOption) applyCombineOption(o *combineOptions) error {
+	o.reader = append(o.reader, d)
+	return nil
+}
+
 type separatorOption SeparatorFunc
 
 func (s separatorOption) applyWriterOption(o *writerOptions) error {
@@ -103,10 +168,245 @@ func (s separatorOption) applyWriterOption(o *writerOptions) error {
 	if o.separator != nil {
 		return errors.New("separator function is already set")
 	}
+	if o.layout != nil {
+		return errors.New("separator function cannot be combined with a layout")
+	}
 	o.separator = SeparatorFunc(s)
 	return nil
 }
 
+func (s separatorOption) applySplitOption(o *splitOptions) error {
+	o.writer = append(o.writer, s)
+	return nil
+}
+
 func WithSeparator(f SeparatorFunc) WriterOption {
 	return separatorOption(f)
 }
+
+type sequentialShardsOption struct{}
+
+func (s sequentialShardsOption) applySplitOption(o *splitOptions) error {
+	if o.sequential {
+		return errors.New("sequential shard numbers are already set")
+	}
+	o.sequential = true
+	return nil
+}
+
+// WithSequentialShards numbers shards from one to the total number of shards. The numbers are encoded into the shards, so that they can be printed alongside the shards and checked by [ShardNumber] during recovery.
+func WithSequentialShards() SplitOption {
+	return sequentialShardsOption{}
+}
+
+type backendOption struct {
+	backend shamir.Backend
+}
+
+func (b backendOption) applySplitOption(o *splitOptions) error {
+	if b.backend == nil {
+		return errors.New("cannot use a <nil> secret sharing backend")
+	}
+	if o.backend != nil {
+		return errors.New("secret sharing backend is already set")
+	}
+	o.backend = b.backend
+	return nil
+}
+
+func (b backendOption) applyCombineOption(o *combineOptions) error {
+	if b.backend == nil {
+		return errors.New("cannot use a <nil> secret sharing backend")
+	}
+	if o.backend != nil {
+		return errors.New("secret sharing backend is already set")
+	}
+	o.backend = b.backend
+	return nil
+}
+
+// WithBackend selects the secret sharing arithmetic, which is [shamir.GF256] by default. The shards record the backend, so [Combine] picks it automatically, unless the backend is a [shamir.PrimeField] with a custom prime, which must be provided to [Combine] again. The shards record a digest of the custom prime, so a different prime is rejected. Other custom backends are not recorded and cannot be checked.
+func WithBackend(b shamir.Backend) ShardOption {
+	return backendOption{backend: b}
+}
+
+type passphraseOption Secret
+
+func (p passphraseOption) applySplitOption(o *splitOptions) error {
+	if len(p) == 0 {
+		return errors.New("passphrase is empty")
+	}
+	if o.passphrase != nil {
+		return errors.New("passphrase is already set")
+	}
+	o.passphrase = Secret(p)
+	return nil
+}
+
+func (p passphraseOption) applyCombineOption(o *combineOptions) error {
+	if len(p) == 0 {
+		return errors.New("passphrase is empty")
+	}
+	if o.passphrase != nil {
+		return errors.New("passphrase is already set")
+	}
+	o.passphrase = Secret(p)
+	return nil
+}
+
+// WithPassphrase encrypts the key with a key derived from the passphrase by Argon2id before splitting it. [Combine] requires both a quorum of shards and the same passphrase, so that whoever gathers the shards cannot recover the key without also knowing the passphrase. The passphrase is not copied, and it is wiped once the shards are split or combined, so the option cannot be used twice.
+func WithPassphrase(passphrase Secret) ShardOption {
+	return passphraseOption(passphrase)
+}
+
+type argonOption ArgonParameters
+
+func (a argonOption) applySplitOption(o *splitOptions) error {
+	if o.argon != nil {
+		return errors.New("Argon parameters are already set")
+	}
+	p := ArgonParameters(a)
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	o.argon = &p
+	return nil
+}
+
+// WithArgonParameters sets the costs of passphrase key derivation, which are [DefaultArgonParameters] otherwise. The parameters are recorded in the shards, so [Combine] does not need them. Requires [WithPassphrase].
+func WithArgonParameters(p ArgonParameters) SplitOption {
+	return argonOption(p)
+}
+
+type decoyOption struct {
+	passphrase Secret
+	secret     Secret
+}
+
+func (d decoyOption) applySplitOption(o *splitOptions) error {
+	if len(d.passphrase) == 0 {
+		return errors.New("decoy passphrase is empty")
+	}
+	if o.decoy != nil {
+		return errors.New("decoy secret is already set")
+	}
+	o.decoy = &sealSlot{
+		secret:     d.secret,
+		passphrase: d.passphrase,
+	}
+	return nil
+}
+
+// WithDecoy hides a second secret in the shards, which [Combine] recovers instead of the key when given the decoy passphrase, so that a coerced holder can give up the decoy passphrase while keeping the key safe. It implies [WithDeniability], and the decoy must pad to the same size as the key, because the revealed decoy would not account for the length of the shards otherwise. Like the passphrase, both are wiped once the shards are split. Requires [WithPassphrase].
+func WithDecoy(passphrase, secret Secret) SplitOption {
+	return decoyOption{passphrase: passphrase, secret: secret}
+}
+
+type deniabilityOption struct{}
+
+func (deniabilityOption) applySplitOption(o *splitOptions) error {
+	if o.deniable {
+		return errors.New("deniability is already set")
+	}
+	o.deniable = true
+	return nil
+}
+
+// WithDeniability reserves room for a decoy secret, so that shards sealed without [WithDecoy] look the same as shards with one, and nobody can tell whether a decoy exists. Secrets are padded to 64 bytes, or the next power of two that fits, and sealed next to a slot of the same size, which makes the shards much longer than the default padding to eight bytes. Requires [WithPassphrase].
+func WithDeniability() SplitOption {
+	return deniabilityOption{}
+}
+
+type timeLockOption TimeLock
+
+func (t timeLockOption) applySplitOption(o *splitOptions) error {
+	if o.timeLock != nil {
+		return errors.New("time-lock is already set")
+	}
+	lock := TimeLock(t)
+	if err := lock.Validate(); err != nil {
+		return err
+	}
+	o.timeLock = &lock
+	return nil
+}
+
+// WithTimeLock wraps the key into a [TimeLock] puzzle, so that even a quorum of shards must be followed by deliberate, slow work to recover the key. Use [CalibrateTimeLock] to estimate the parameters.
+func WithTimeLock(t TimeLock) SplitOption {
+	return timeLockOption(t)
+}
+
+type timeLockProgressOption func(done, total uint64)
+
+func (p timeLockProgressOption) applyCombineOption(o *combineOptions) error {
+	if p == nil {
+		return errors.New("cannot use a <nil> progress function")
+	}
+	if o.progress != nil {
+		return errors.New("time-lock progress function is already set")
+	}
+	o.progress = p
+	return nil
+}
+
+// WithTimeLockProgress reports progress of solving a [TimeLock] puzzle after every step.
+func WithTimeLockProgress(f func(done, total uint64)) CombineOption {
+	return timeLockProgressOption(f)
+}
+
+type legacyShardsOption struct{}
+
+func (l legacyShardsOption) applyCombineOption(o *combineOptions) error {
+	if o.legacy {
+		return errors.New("legacy shards are already set")
+	}
+	o.legacy = true
+	return nil
+}
+
+// WithLegacyShards combines shards without headers, which were created by earlier versions of [Split], by plain [shamir.Combine]. [Combine] falls back to it on its own only when the header of no shard parses, because a mistyped header of a newer shard would otherwise be combined into a wrong key without an error.
+func WithLegacyShards() CombineOption {
+	return legacyShardsOption{}
+}
+
+type compressionOption struct{}
+
+func (c compressionOption) applyWriterOption(o *writerOptions) error {
+	if o.compress {
+		return errors.New("compression is already set")
+	}
+	o.compress = true
+	return nil
+}
+
+func (c compressionOption) applySplitOption(o *splitOptions) error {
+	return errors.New("shards are random and cannot be compressed")
+}
+
+// WithCompression compresses the payload of a [Container] with the codec that makes it shortest: DEFLATE, DEFLATE primed with the BIP39 word list for text made of common words, or none at all. Long passwords and notes produce fewer words. Random data, like keys, does not compress, so the option is rejected by [NewWriter], [Split] and [GeneratePassphrase], which have no container to record the codec.
+func WithCompression() WriterOption {
+	return compressionOption{}
+}
+
+type payloadKindOption PayloadKind
+
+func (k payloadKindOption) applyWriterOption(o *writerOptions) error {
+	if o.kindSet {
+		return errors.New("payload kind is already set")
+	}
+	if PayloadKind(k) > PayloadChecksummed {
+		return fmt.Errorf("%s is not supported", PayloadKind(k))
+	}
+	o.kind = PayloadKind(k)
+	o.kindSet = true
+	return nil
+}
+
+func (k payloadKindOption) applySplitOption(o *splitOptions) error {
+	return errors.New("shards are not framed in a container")
+}
+
+// WithPayloadKind records what the payload of a [Container] holds, which is [PayloadData] otherwise. The option is rejected by [NewWriter], [Split] and [GeneratePassphrase], which have no container to record it.
+func WithPayloadKind(kind PayloadKind) WriterOption {
+	return payloadKindOption(kind)
+}
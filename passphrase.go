@@ -0,0 +1,220 @@
+package kidwords
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Sealed secrets start with a header made of the format version, the number of slots and the Argon2id parameters, followed by the salt and the slots, each encrypted by AES-256-GCM under the key derived from its own passphrase. The header is authenticated, so that the costs cannot be lowered by tampering with the shards.
+const (
+	sealVersion    = 1
+	sealSaltSize   = 16
+	sealKeySize    = 32
+	sealTagSize    = 16
+	sealBlockSize  = 8               // the padding step of a single slot
+	sealSlotSize   = 64              // the smallest deniable slot, which fits keys and passwords
+	maxSealSlots   = 2               // the secret and a decoy
+	maxArgonMemory = 4 * 1024 * 1024 // KiB
+)
+
+// ArgonParameters are the costs of deriving a key from a passphrase by Argon2id. They follow the model of store.ArgonHash, field by field, and [DefaultArgonParameters] match store.DefaultArgonTimeCost, store.DefaultArgonMemoryCost and store.DefaultArgonThreads. The store module is separate, pulls in database drivers, and misses requirements in its go.mod, so it is not imported to share the type; keep the two in step.
+type ArgonParameters struct {
+	TimeCost        uint32
+	MemoryCost      uint32 // in KiB
+	ParallelThreads uint8
+}
+
+// DefaultArgonParameters are recommended by x/crypto/argon2 for interactive use.
+var DefaultArgonParameters = ArgonParameters{
+	TimeCost:        1,
+	MemoryCost:      64 * 1024,
+	ParallelThreads: 4,
+}
+
+// Validate checks that the costs are usable.
+func (a ArgonParameters) Validate() error {
+	if a.TimeCost < 1 {
+		return errors.New("Argon time cost must be at least 1")
+	}
+	if a.ParallelThreads < 1 {
+		return errors.New("Argon parallel threads must be at least 1")
+	}
+	if a.MemoryCost < 8*uint32(a.ParallelThreads) || a.MemoryCost > maxArgonMemory {
+		return fmt.Errorf("Argon memory cost %d KiB is out of range [%d-%d]", a.MemoryCost, 8*uint32(a.ParallelThreads), maxArgonMemory)
+	}
+	return nil
+}
+
+func (a ArgonParameters) key(passphrase, salt []byte) []byte {
+	return argon2.IDKey(passphrase, salt, a.TimeCost, a.MemoryCost, a.ParallelThreads, sealKeySize)
+}
+
+func newSealCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealSlot is a secret and the passphrase that opens it.
+type sealSlot struct {
+	secret     []byte
+	passphrase []byte
+}
+
+// sealPadding returns the size of the slot that holds a secret of the given length and its padding byte. A single slot is padded to the next multiple of eight bytes to stay short enough for paper. Deniable slots take 64 bytes, doubled until the secret fits, so that a slot only tells which step of the fixed ladder the secret fell on.
+func sealPadding(length int, deniable bool) int {
+	if !deniable {
+		return (length/sealBlockSize + 1) * sealBlockSize
+	}
+	size := sealSlotSize
+	for size < length+1 {
+		size *= 2
+	}
+	return size
+}
+
+// seal encrypts the secret with a key derived from the passphrase into a single slot. Deniable secrets hold two slots of equal length in random order, padded by [sealPadding]. The second slot holds the decoy secret, when one is given, or random bytes, so that nobody can tell whether a decoy exists. A decoy must pad to the same size as the secret, so that the revealed decoy accounts for the length of the shards by itself. Every salt and passphrase produce a new key, so the nonce can stay zero.
+func seal(real sealSlot, decoy *sealSlot, deniable bool, a ArgonParameters) ([]byte, error) {
+	if len(real.passphrase) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	deniable = deniable || decoy != nil
+	slotCount := 1
+	if deniable {
+		slotCount = maxSealSlots
+	}
+	header := []byte{sealVersion, uint8(slotCount)}
+	header = binary.AppendUvarint(header, uint64(a.TimeCost))
+	header = binary.AppendUvarint(header, uint64(a.MemoryCost))
+	header = append(header, a.ParallelThreads)
+
+	salt := make([]byte, sealSaltSize+1)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	order := salt[sealSaltSize] & 1
+	salt = salt[:sealSaltSize]
+
+	size := sealPadding(len(real.secret), deniable)
+	if decoy != nil {
+		if len(decoy.passphrase) == 0 {
+			return nil, errors.New("decoy passphrase is empty")
+		}
+		if bytes.Equal(decoy.passphrase, real.passphrase) {
+			return nil, errors.New("decoy passphrase must differ from the passphrase")
+		}
+		if decoySize := sealPadding(len(decoy.secret), true); decoySize != size {
+			return nil, fmt.Errorf("decoy secret pads to %d bytes, but the secret pads to %d bytes, which would reveal the decoy; choose a decoy of similar length", decoySize, size)
+		}
+	}
+
+	slots := make([][]byte, slotCount)
+	for i, slot := range []*sealSlot{&real, decoy}[:slotCount] {
+		if slot == nil {
+			filler := make([]byte, size+sealTagSize)
+			if _, err := rand.Read(filler); err != nil {
+				return nil, err
+			}
+			slots[i] = filler
+			continue
+		}
+		key := Secret(a.key(slot.passphrase, salt))
+		aead, err := newSealCipher(key)
+		key.Wipe()
+		if err != nil {
+			return nil, err
+		}
+		// Pad the secret with a single 0x80 byte followed by zeros to hide its length.
+		padded := make(Secret, size)
+		copy(padded, slot.secret)
+		padded[len(slot.secret)] = 0x80
+		slots[i] = aead.Seal(nil, make([]byte, aead.NonceSize()), padded, header)
+		padded.Wipe()
+	}
+
+	sealed := append(header, salt...)
+	if slotCount == 1 {
+		return append(sealed, slots[0]...), nil
+	}
+	return append(append(sealed, slots[order]...), slots[1-order]...), nil
+}
+
+// unseal reverses [seal] by opening the slot that matches the passphrase.
+func unseal(sealed, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("shards are protected by a passphrase, which was not provided")
+	}
+	if len(sealed) < 1 || sealed[0] != sealVersion {
+		return nil, errors.New("sealed secret version is not supported")
+	}
+	if len(sealed) < 2 || sealed[1] < 1 || sealed[1] > maxSealSlots {
+		return nil, errors.New("sealed secret slot count is corrupt")
+	}
+	var (
+		a         ArgonParameters
+		slotCount = int(sealed[1])
+		cursor    = 2
+	)
+	time, n := binary.Uvarint(sealed[cursor:])
+	if n <= 0 || time > 1<<32-1 {
+		return nil, errors.New("sealed secret time cost is corrupt")
+	}
+	cursor += n
+	memory, n := binary.Uvarint(sealed[cursor:])
+	if n <= 0 || memory > maxArgonMemory {
+		return nil, errors.New("sealed secret memory cost is corrupt")
+	}
+	cursor += n
+	if len(sealed) < cursor+1+sealSaltSize {
+		return nil, errors.New("sealed secret is too short")
+	}
+	a.TimeCost = uint32(time)
+	a.MemoryCost = uint32(memory)
+	a.ParallelThreads = sealed[cursor]
+	cursor++
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	header, salt := sealed[:cursor], sealed[cursor:cursor+sealSaltSize]
+	slots := sealed[cursor+sealSaltSize:]
+	if len(slots)%slotCount != 0 || len(slots) < slotCount*(sealTagSize+1) {
+		return nil, errors.New("sealed secret is corrupt")
+	}
+	slotSize := len(slots) / slotCount
+	key := Secret(a.key(passphrase, salt))
+	aead, err := newSealCipher(key)
+	key.Wipe()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	for i := 0; i < slotCount; i++ {
+		padded, err := aead.Open(nil, nonce, slots[i*slotSize:(i+1)*slotSize], header)
+		if err != nil {
+			continue
+		}
+		end := len(padded) - 1
+		for end >= 0 && padded[end] == 0 {
+			end--
+		}
+		if end < 0 || padded[end] != 0x80 {
+			Secret(padded).Wipe()
+			return nil, errors.New("sealed secret padding is corrupt")
+		}
+		return padded[:end], nil
+	}
+	return nil, errors.New("passphrase is incorrect or shards are corrupt")
+}
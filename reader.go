@@ -5,8 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/dkotik/kidwords/dictionary"
 )
@@ -27,75 +27,141 @@ func NewReader(r io.Reader, withOptions ...ReaderOption) (*Reader, error) {
 	if o.dictionary == nil {
 		o.dictionary = (&dictionary.EnglishFourLetterNouns).Reverse()
 	}
+	reader := newReader(r, newWordMatcher(o.dictionary, o.stripDiacritics))
+	reader.erasable = o.erasures
+	if o.layout != nil && o.layout.Checksum {
+		reader.layout = o.layout
+	}
+	return reader, nil
+}
 
+func newReader(r io.Reader, matcher *wordMatcher) *Reader {
 	return &Reader{
-		r:          bufio.NewReader(r),
-		dictionary: o.dictionary,
-	}, nil
-	// reader.Scanner.Error = func(s *scanner.Scanner, msg string) {
-	// 	reader.scanErr = errors.New(msg)
-	// }
-	// reader.Scanner.IsIdentRune
-	// reader.Scanner.Init(r)
-	// return reader
+		r:       bufio.NewReader(r),
+		matcher: matcher,
+		line:    1,
+		column:  1,
+	}
 }
 
+// Reader decodes Kid Words into bytes. Any characters other than letters and combining marks separate words. Words match the dictionary regardless of case and Unicode normalization form, except for dictionary words that differ only by case, which must match exactly, and words typed together, like "farmline", are split when there is only one way to do it.
 type Reader struct {
-	r *bufio.Reader
-	// scanErr    error
-	dictionary map[string]byte
+	r       *bufio.Reader
+	matcher *wordMatcher
+	word    []byte // reused between words to avoid allocations
+	matched []byte // bytes of the last word
+	popped  int    // number of matched bytes already returned
+	index   int    // number of words decoded so far
+	line    int    // line of the next rune
+	column  int    // column of the next rune
+	err     error
+
+	// erasable is set when placeholders stand for missing words
+	erasable bool
+	erasures []int // positions of placeholders in the decoded data
+
+	// layout is set when lines end with check words
+	layout  *Layout
+	lines   int    // number of verified lines
+	skipped int    // number of check words removed from the decoded data
+	pending []byte // words of the current line and its check word
+	ready   []byte // verified words that were not read yet
+}
+
+// WordError reports a word that is not in the dictionary and its position in the input, so that it can be highlighted.
+type WordError struct {
+	Word string
+	// Index counts the words that precede the bad word.
+	Index int
+	// Line starts at 1.
+	Line int
+	// Column starts at 1 and counts runes, not bytes.
+	Column int
 }
 
+func (e *WordError) Error() string {
+	return fmt.Sprintf("word %q on line %d, column %d is not in the dictionary", e.Word, e.Line, e.Column)
+}
+
+// Read decodes words until p is full or the input ends. It returns a [*WordError] when a word is not in the dictionary and a [*LineChecksumError] when a line of a [Layout] does not match its check word. Bytes decoded before the error are returned first, and the error is returned by the following call.
 func (r *Reader) Read(p []byte) (n int, err error) {
-	var rn rune
-	var b = strings.Builder{}
+	if r.layout != nil {
+		return r.readLines(p)
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	for n < len(p) {
+		if p[n], err = r.next(); err != nil {
+			r.err = err
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		n++
+	}
+	return n, nil
+}
 
+func (r *Reader) next() (byte, error) {
+	if r.popped < len(r.matched) {
+		return r.pop(), nil
+	}
+	var line, column int
+	r.word = r.word[:0]
 	for {
-		rn, _, err = r.r.ReadRune()
+		rn, _, err := r.r.ReadRune()
 		if err != nil {
-			if err == io.EOF && b.Len() > 0 {
-				data, ok := r.dictionary[b.String()]
-				if !ok {
-					return 0, fmt.Errorf("word %q is not in the dictionary", b.String())
-				}
-				p[0] = data
-				return 1, nil
+			if err == io.EOF && len(r.word) > 0 {
+				return r.lookup(line, column)
 			}
 			return 0, err
 		}
-		if !unicode.IsLetter(rn) {
-			if b.Len() > 0 {
-				data, ok := r.dictionary[b.String()]
-				if !ok {
-					return 0, fmt.Errorf("word %q is not in the dictionary", b.String())
-				}
-				p[0] = data
-				return 1, nil
+		if r.erasable && isPlaceholder(rn) {
+			if len(r.word) > 0 {
+				_ = r.r.UnreadRune()
+				return r.lookup(line, column)
 			}
-			continue // skip non-letters
+			return r.erase()
+		}
+		if unicode.IsLetter(rn) || len(r.word) > 0 && unicode.IsMark(rn) {
+			if len(r.word) == 0 {
+				line, column = r.line, r.column
+			}
+			r.word = utf8.AppendRune(r.word, rn)
+			r.column++
+			continue
+		}
+		if rn == '\n' {
+			r.line++
+			r.column = 1
+		} else {
+			r.column++
+		}
+		if len(r.word) > 0 {
+			return r.lookup(line, column)
+		}
+	}
+}
+
+func (r *Reader) lookup(line, column int) (byte, error) {
+	var ok bool
+	r.popped = 0
+	if r.matched, ok = r.matcher.match(r.matched[:0], r.word); !ok {
+		return 0, &WordError{
+			Word:   string(r.word),
+			Index:  r.index,
+			Line:   line,
+			Column: column,
 		}
-		_, _ = b.WriteRune(rn)
 	}
+	return r.pop(), nil
 }
 
-// func (r *Reader) Read(p []byte) (n int, err error) {
-// 	// for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
-// 	//
-// 	// }
-// 	for i := range p {
-// 		c := r.Scan()
-// 		if c == scanner.EOF {
-// 			return n, io.EOF
-// 		}
-// 		if r.scanErr != nil {
-// 			return n, r.scanErr
-// 		}
-// 		b, ok := r.dictionary[r.TokenText()]
-// 		if !ok {
-// 			return n, fmt.Errorf("word %q is not in chosen dictionary", r.TokenText())
-// 		}
-// 		p[i] = b
-// 		n++
-// 	}
-// 	return
-// }
+func (r *Reader) pop() byte {
+	b := r.matched[r.popped]
+	r.popped++
+	r.index++
+	return b
+}
@@ -2,6 +2,8 @@ package kidwords
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"testing"
@@ -24,3 +26,231 @@ func TestReader(t *testing.T) {
 
 	test.GoldenMust(t, "test/testdata/readRaw.golden", b.Bytes())
 }
+
+func TestReaderFillsBuffer(t *testing.T) {
+	words, err := FromBytes([]byte("marvelous"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReader(strings.NewReader(words))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := make([]byte, 6)
+	n, err := r.Read(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(p) || string(p) != "marvel" {
+		t.Fatalf("read %d bytes %q instead of filling the buffer", n, p[:n])
+	}
+	n, err = r.Read(p)
+	if err != nil || string(p[:n]) != "ous" {
+		t.Fatalf("read %q with error %v", p[:n], err)
+	}
+	if _, err = r.Read(p); err != io.EOF {
+		t.Fatalf("expected end of file, got %v", err)
+	}
+}
+
+func TestReaderWordError(t *testing.T) {
+	r, err := NewReader(strings.NewReader("idea half\n  icon ñame cell"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := make([]byte, 8)
+	n, err := r.Read(p)
+	if err != nil || n != 3 {
+		t.Fatalf("expected three bytes before the bad word, got %d and error %v", n, err)
+	}
+	_, err = r.Read(p)
+	var wordErr *WordError
+	if !errors.As(err, &wordErr) {
+		t.Fatalf("expected a word error, got %v", err)
+	}
+	if *wordErr != (WordError{Word: "ñame", Index: 3, Line: 2, Column: 8}) {
+		t.Fatalf("unexpected word error %+v", *wordErr)
+	}
+	if _, again := r.Read(p); again != err {
+		t.Fatal("error was not repeated")
+	}
+}
+
+func TestReaderAllocations(t *testing.T) {
+	words, err := FromBytes(bytes.Repeat([]byte{0, 127, 255}, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := strings.NewReader(words)
+	r, err := NewReader(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := make([]byte, 300)
+	if _, err = io.ReadFull(r, p); err != nil { // warm up the word buffer
+		t.Fatal(err)
+	}
+	allocations := testing.AllocsPerRun(10, func() {
+		in.Reset(words)
+		r.r.Reset(in)
+		if _, err := io.ReadFull(r, p); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocations > 0 {
+		t.Fatalf("reader allocated %.0f times per run", allocations)
+	}
+}
+
+func TestReaderLineChecksums(t *testing.T) {
+	layout := WithLayout(Layout{GroupSize: 3, GroupsPerLine: 2, Checksum: true})
+	cases := []struct {
+		Name  string
+		Words string
+		Line  int
+	}{
+		{Name: "correct", Words: "1. hole gold hush  item half hint = flow\n2. hook iron icon = rose"},
+		{Name: "retyped on one line", Words: "hole gold hush item half hint flow hook iron icon rose"},
+		{Name: "mistyped word", Words: "1. hole gold hush  item half hint = flow\n2. hook iron idea = rose", Line: 2},
+		{Name: "mistyped check word", Words: "1. hole gold hush  item half hint = fork\n2. hook iron icon = rose", Line: 1},
+		{Name: "swapped words", Words: "1. hole gold hush  item hint half = flow\n2. hook iron icon = rose", Line: 1},
+		{Name: "missing check word", Words: "1. hole gold hush  item half hint = flow\n2. hook iron icon", Line: 2},
+		{Name: "check word alone", Words: "1. hole gold hush  item half hint = flow\n2. rose", Line: 2},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			decoded, err := ToString(c.Words, layout)
+			if c.Line == 0 {
+				if err != nil || decoded != "marvelous" {
+					t.Fatalf("decoded %q with error %v", decoded, err)
+				}
+				return
+			}
+			var lineErr *LineChecksumError
+			if !errors.As(err, &lineErr) {
+				t.Fatalf("expected a line checksum error, got %v", err)
+			}
+			if lineErr.Line != c.Line {
+				t.Fatalf("reported line %d instead of %d", lineErr.Line, c.Line)
+			}
+		})
+	}
+
+	// lines are numbered into their check words, so a skipped line is caught
+	if _, err := ToString("hook iron icon rose", layout); err == nil {
+		t.Fatal("skipped line was accepted")
+	}
+}
+
+func TestReaderNormalization(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Words   string
+		Options []ReaderOption
+	}{
+		{Name: "exact", Words: "hole gold hush item half hint"},
+		{Name: "case", Words: "Hole GOLD hUsh item half hint"},
+		{Name: "run together", Words: "holegold hushitemhalf hint"},
+		{Name: "run together with case", Words: "HoleGold HushItem HalfHint"},
+		{Name: "full width", Words: "ｈｏｌｅ gold hush item half hint"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			decoded, err := ToString(c.Words, c.Options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded != "marvel" {
+				t.Fatalf("decoded %q", decoded)
+			}
+		})
+	}
+
+	d := dictionary.EnglishFourLetterNouns
+	d['m'] = "niño" // replaces "hole"
+	d['a'] = "be"
+	d['r'] = "ar"
+	for _, words := range []string{"ni\u00f1o", "nin\u0303o", "NI\u00d1O"} {
+		decoded, err := ToString(words, WithDictionary(&d))
+		if err != nil || decoded != "m" {
+			t.Fatalf("%q decoded into %q with error %v", words, decoded, err)
+		}
+	}
+	if _, err := ToString("nino", WithDictionary(&d)); err == nil {
+		t.Fatal("word without diacritics was accepted without folding")
+	}
+	decoded, err := ToString("nino", WithDictionary(&d), WithDiacriticFolding())
+	if err != nil || decoded != "m" {
+		t.Fatalf("decoded %q with error %v", decoded, err)
+	}
+
+	var wordErr *WordError
+	if _, err = ToString("bearbe", WithDictionary(&d)); !errors.As(err, &wordErr) {
+		t.Fatalf("ambiguous words were accepted: %v", err)
+	}
+
+	d['z'] = "nino"
+	for words, expected := range map[string]string{"nino": "z", "niño": "m"} {
+		if decoded, err = ToString(words, WithDictionary(&d), WithDiacriticFolding()); err != nil || decoded != expected {
+			t.Fatalf("%q decoded into %q with error %v", words, decoded, err)
+		}
+	}
+	if _, err = ToString("Nino", WithDictionary(&d), WithDiacriticFolding()); err == nil {
+		t.Fatal("word that folds into two dictionary words was accepted")
+	}
+
+	d['z'] = "Idea"
+	encoding := NewEncoding(&d) // must not panic
+	for words, expected := range map[string]byte{"Idea": 'z', "idea": d.Reverse()["idea"]} {
+		b, err := encoding.DecodeString(words)
+		if err != nil || !bytes.Equal(b, []byte{expected}) {
+			t.Fatalf("%q decoded into %v with error %v", words, b, err)
+		}
+	}
+	if _, err = ToString("IDEA", WithDictionary(&d)); err == nil {
+		t.Fatal("word that differs from two dictionary words by case only was accepted")
+	}
+}
+
+func TestReaderErasures(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Words    string
+		Options  []ReaderOption
+		Expected string
+		Erasures []int
+	}{
+		{Name: "question mark", Words: "hole ? hush item half hint", Expected: "m\x00rvel", Erasures: []int{1}},
+		{Name: "underscores", Words: "____ gold hush item half?", Expected: "\x00arve\x00", Erasures: []int{0, 5}},
+		{Name: "run of placeholders", Words: "hole gold ??? item half hint", Expected: "ma\x00vel", Erasures: []int{2}},
+		{
+			Name:     "line checksums",
+			Words:    "1. hole gold hush  ____ half hint = flow\n2. hook iron icon = ?",
+			Options:  []ReaderOption{WithLayout(Layout{GroupSize: 3, GroupsPerLine: 2, Checksum: true})},
+			Expected: "mar\x00elous",
+			Erasures: []int{3},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			r, err := NewReader(strings.NewReader(c.Words), append(c.Options, WithErasures())...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(decoded) != c.Expected {
+				t.Fatalf("decoded %q instead of %q", decoded, c.Expected)
+			}
+			if fmt.Sprint(r.Erasures()) != fmt.Sprint(c.Erasures) {
+				t.Fatalf("erasures %v do not match %v", r.Erasures(), c.Erasures)
+			}
+		})
+	}
+
+	if _, err := ToBytes("hole ? hush"); err != nil {
+		t.Fatal("question mark is not a separator without erasures option")
+	}
+}
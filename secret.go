@@ -0,0 +1,22 @@
+package kidwords
+
+import "runtime"
+
+// Secret holds sensitive bytes, like keys and passphrases. Unlike a string, which Go copies freely and never clears, a Secret can be wiped from memory as soon as it is no longer needed. Formatting a Secret prints a placeholder, so that it does not leak into logs.
+type Secret []byte
+
+// Wipe overwrites the secret with zeros.
+func (s Secret) Wipe() {
+	clear(s)
+	runtime.KeepAlive(s)
+}
+
+// String returns a placeholder instead of the secret.
+func (s Secret) String() string {
+	return "<secret>"
+}
+
+// GoString returns a placeholder instead of the secret.
+func (s Secret) GoString() string {
+	return "kidwords.Secret(<secret>)"
+}
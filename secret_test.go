@@ -0,0 +1,21 @@
+package kidwords
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	secret := Secret("somethingElse")
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%q", "%x"} {
+		if printed := fmt.Sprintf(format, secret); strings.Contains(printed, "something") || strings.Contains(printed, "736f6d65") {
+			t.Fatalf("format %s leaked the secret: %s", format, printed)
+		}
+	}
+	secret.Wipe()
+	if !bytes.Equal(secret, make([]byte, len(secret))) {
+		t.Fatalf("secret was not wiped: %q", []byte(secret))
+	}
+}
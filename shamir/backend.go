@@ -0,0 +1,20 @@
+package shamir
+
+// Backend splits a secret into shares using a particular field arithmetic and combines the shares back. Every share ends with its one byte x coordinate tag, like the shares produced by [Split].
+type Backend interface {
+	Split(secret []byte, parts, threshold int) ([][]byte, error)
+	Combine(parts [][]byte) ([]byte, error)
+}
+
+// GF256 is the default [Backend], which treats each byte of the secret as an intercept of a polynomial over GF(2^8). Its shares are only one byte longer than the secret.
+type GF256 struct{}
+
+// Split calls [Split].
+func (GF256) Split(secret []byte, parts, threshold int) ([][]byte, error) {
+	return Split(secret, parts, threshold)
+}
+
+// Combine calls [Combine].
+func (GF256) Combine(parts [][]byte) ([]byte, error) {
+	return Combine(parts)
+}
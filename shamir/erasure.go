@@ -0,0 +1,65 @@
+package shamir
+
+import "fmt"
+
+// CombineErasures works like [Combine], but ignores the erased bytes of each part, which are listed by their positions in erasures, one list per part. Every byte of the secret is recovered from the parts that kept it, so that parts torn in different places complement each other. Each byte must be kept by at least threshold parts, the number that [Split] was given, because interpolating fewer samples silently produces a wrong byte.
+func CombineErasures(parts [][]byte, erasures [][]int, threshold int) ([]byte, error) {
+	if len(erasures) != len(parts) {
+		return nil, fmt.Errorf("%d erasure lists provided for %d parts", len(erasures), len(parts))
+	}
+	if threshold < 2 || threshold > 255 {
+		return nil, fmt.Errorf("threshold %d is out of range [2-255]", threshold)
+	}
+	if len(parts) < threshold {
+		return nil, fmt.Errorf("%d parts provided, but %d are required to reconstruct the secret", len(parts), threshold)
+	}
+	partLen := len(parts[0])
+	if partLen < 2 {
+		return nil, fmt.Errorf("parts must be at least two bytes")
+	}
+
+	erased := make([][]bool, len(parts))
+	for i, part := range parts {
+		if len(part) != partLen {
+			return nil, fmt.Errorf("all parts must be the same length")
+		}
+		erased[i] = make([]bool, partLen)
+		for _, position := range erasures[i] {
+			if position < 0 || position >= partLen {
+				return nil, fmt.Errorf("erasure position %d of part %d is out of range [0-%d]", position, i+1, partLen-1)
+			}
+			erased[i][position] = true
+		}
+		if erased[i][partLen-1] {
+			return nil, fmt.Errorf("tag of part %d is erased", i+1)
+		}
+	}
+
+	checkMap := map[byte]bool{}
+	for _, part := range parts {
+		if checkMap[part[partLen-1]] {
+			return nil, fmt.Errorf("duplicate part detected")
+		}
+		checkMap[part[partLen-1]] = true
+	}
+
+	secret := make([]byte, partLen-1)
+	x_samples := make([]uint8, 0, len(parts))
+	y_samples := make([]uint8, 0, len(parts))
+	for position := range secret {
+		x_samples, y_samples = x_samples[:0], y_samples[:0]
+		for i, part := range parts {
+			if !erased[i][position] {
+				x_samples = append(x_samples, part[partLen-1])
+				y_samples = append(y_samples, part[position])
+			}
+		}
+		if len(x_samples) < threshold {
+			return nil, fmt.Errorf("byte %d is kept by %d parts, but %d are required", position, len(x_samples), threshold)
+		}
+		for i, basis := range lagrangeBasis(x_samples, 0) {
+			secret[position] ^= mult(y_samples[i], basis)
+		}
+	}
+	return secret, nil
+}
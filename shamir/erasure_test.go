@@ -0,0 +1,45 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCombineErasures(t *testing.T) {
+	secret := []byte("torn paper key")
+	parts, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every byte is kept by at least three of four parts
+	erasures := [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}, {9, 10, 11, 12, 13}}
+	for i, part := range parts[:4] {
+		for _, position := range erasures[i] {
+			part[position] = 0
+		}
+	}
+	recovered, err := CombineErasures(parts[:4], erasures, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Fatalf("recovered %q instead of %q", recovered, secret)
+	}
+
+	if _, err = CombineErasures(parts[:4], erasures[:3], 3); err == nil {
+		t.Fatal("missing erasure list was accepted")
+	}
+	if _, err = CombineErasures(parts[:3], [][]int{{0}, nil, nil}, 3); err == nil {
+		t.Fatal("byte kept by fewer parts than the threshold was accepted")
+	}
+	if _, err = CombineErasures(parts[:2], [][]int{nil, nil}, 3); err == nil {
+		t.Fatal("fewer parts than the threshold were accepted")
+	}
+	if _, err = CombineErasures(parts[:3], [][]int{{len(secret)}, nil, nil}, 3); err == nil {
+		t.Fatal("erased tag was accepted")
+	}
+	if _, err = CombineErasures(parts[:3], [][]int{{-1}, nil, nil}, 3); err == nil {
+		t.Fatal("negative erasure position was accepted")
+	}
+}
@@ -0,0 +1,133 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// Arithmetic in GF(2^8) modulo x^8 + x^4 + x^3 + x + 1, which is the
+// AES field. None of the functions below use lookup tables or branch on
+// field elements, so their timing does not depend on the secret. The
+// vector helpers pack eight field elements into one 64 bit word and
+// multiply them by the same scalar at once, which is how polynomials
+// are evaluated and interpolated for every byte of the secret together.
+
+const (
+	laneLowBits  = 0x7f7f7f7f7f7f7f7f
+	laneHighBits = 0x8080808080808080
+)
+
+// vector holds field elements packed eight per word in little endian order.
+type vector []uint64
+
+// loadVector packs bytes into a [vector], padding the last word with zeros.
+func loadVector(b []byte) vector {
+	v := make(vector, (len(b)+7)/8)
+	var word [8]byte
+	for i := range v {
+		n := copy(word[:], b[i*8:])
+		for j := n; j < 8; j++ {
+			word[j] = 0
+		}
+		v[i] = binary.LittleEndian.Uint64(word[:])
+	}
+	return v
+}
+
+// randomVector fills a [vector] of n field elements with random values.
+func randomVector(n int) (vector, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	v := loadVector(b)
+	clear(b)
+	return v, nil
+}
+
+// store unpacks the first len(b) field elements into b.
+func (v vector) store(b []byte) {
+	var word [8]byte
+	for i := range v {
+		binary.LittleEndian.PutUint64(word[:], v[i])
+		copy(b[i*8:], word[:])
+	}
+}
+
+// wipe overwrites the field elements with zeros, so that polynomial
+// coefficients and interpolated secrets do not linger in memory.
+func (v vector) wipe() {
+	clear(v)
+}
+
+// wipeVectors calls [vector.wipe] on every vector.
+func wipeVectors(vectors []vector) {
+	for _, v := range vectors {
+		v.wipe()
+	}
+}
+
+// mulAdd computes v += src * c.
+func (v vector) mulAdd(src vector, c uint8) {
+	for i := range v {
+		v[i] ^= mult8(src[i], c)
+	}
+}
+
+// xtime8 multiplies each of the eight packed field elements by x.
+func xtime8(a uint64) uint64 {
+	return (a&laneLowBits)<<1 ^ ((a&laneHighBits)>>7)*0x1b
+}
+
+// mult8 multiplies each of the eight packed field elements by b.
+func mult8(a uint64, b uint8) (r uint64) {
+	for i := 7; i >= 0; i-- {
+		r = xtime8(r) ^ (a & -uint64(b>>i&1))
+	}
+	return r
+}
+
+// evaluateVector computes the value of polynomials at x using Horner's
+// method. Coefficients are ordered from the intercept to the highest degree,
+// and each vector holds the coefficients of the same degree for every
+// polynomial.
+func evaluateVector(coefficients []vector, x uint8) vector {
+	degree := len(coefficients) - 1
+	out := make(vector, len(coefficients[degree]))
+	copy(out, coefficients[degree])
+	for i := degree - 1; i >= 0; i-- {
+		for j := range out {
+			out[j] = mult8(out[j], x) ^ coefficients[i][j]
+		}
+	}
+	return out
+}
+
+// lagrangeBasis computes the Lagrange basis polynomials of the samples at x.
+// Multiplying each sample by its basis and adding the products together
+// recovers the value at x of the polynomial passing through the samples.
+func lagrangeBasis(x_samples []uint8, x uint8) []uint8 {
+	basis := make([]uint8, len(x_samples))
+	for i := range x_samples {
+		basis[i] = 1
+		for j := range x_samples {
+			if i == j {
+				continue
+			}
+			num := add(x, x_samples[j])
+			denom := add(x_samples[i], x_samples[j])
+			basis[i] = mult(basis[i], div(num, denom))
+		}
+	}
+	return basis
+}
+
+// interpolateVector recovers the values at x of polynomials from sample
+// vectors taken at the given x coordinates.
+func interpolateVector(x_samples []uint8, y_samples []vector, x uint8) vector {
+	out := make(vector, len(y_samples[0]))
+	for i, basis := range lagrangeBasis(x_samples, x) {
+		out.mulAdd(y_samples[i], basis)
+	}
+	return out
+}
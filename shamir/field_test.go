@@ -0,0 +1,175 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+// Known answers from FIPS-197 section 4.2, which uses the same field.
+func TestField_KnownAnswers(t *testing.T) {
+	cases := []struct {
+		a, b, product uint8
+	}{
+		{0x57, 0x83, 0xc1},
+		{0x57, 0x13, 0xfe},
+		{0x57, 0x02, 0xae},
+		{0x57, 0x04, 0x47},
+		{0x57, 0x08, 0x8e},
+		{0x57, 0x10, 0x07},
+		{0x53, 0xca, 0x01},
+		{0x02, 0x8d, 0x01},
+	}
+	for _, c := range cases {
+		if out := mult(c.a, c.b); out != c.product {
+			t.Fatalf("Bad: %#x * %#x = %#x, expected %#x", c.a, c.b, out, c.product)
+		}
+		if out := uint8(mult8(uint64(c.a)<<40, c.b) >> 40); out != c.product {
+			t.Fatalf("Bad: packed %#x * %#x = %#x, expected %#x", c.a, c.b, out, c.product)
+		}
+	}
+
+	if out := inverse(0x53); out != 0xca {
+		t.Fatalf("Bad: %#x 0xca", out)
+	}
+	if out := inverse(0x02); out != 0x8d {
+		t.Fatalf("Bad: %#x 0x8d", out)
+	}
+}
+
+func TestField_Inverse(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if out := mult(uint8(a), inverse(uint8(a))); out != 1 {
+			t.Fatalf("Bad: %#x * inverse(%#x) = %#x", a, a, out)
+		}
+	}
+}
+
+func TestField_Mult8(t *testing.T) {
+	for a := 0; a < 256; a++ {
+		var packed uint64
+		for lane := 0; lane < 8; lane++ {
+			packed |= uint64(uint8(a+lane*31)) << (lane * 8)
+		}
+		for b := 0; b < 256; b++ {
+			out := mult8(packed, uint8(b))
+			for lane := 0; lane < 8; lane++ {
+				expected := mult(uint8(a+lane*31), uint8(b))
+				if got := uint8(out >> (lane * 8)); got != expected {
+					t.Fatalf("Bad: lane %d of %#x * %#x = %#x, expected %#x", lane, packed, b, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestVector_LoadStore(t *testing.T) {
+	for _, n := range []int{1, 7, 8, 9, 64, 100} {
+		b := make([]byte, n)
+		if _, err := rand.Read(b); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		out := make([]byte, n)
+		loadVector(b).store(out)
+		if !bytes.Equal(b, out) {
+			t.Fatalf("bad: %v %v", b, out)
+		}
+	}
+}
+
+func TestVector_EvaluateInterpolate(t *testing.T) {
+	secret := []byte("vectorized evaluation")
+	coefficients, err := makeCoefficients(secret, 2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	x_samples := []uint8{7, 99, 201}
+	y_samples := make([]vector, len(x_samples))
+	for i, x := range x_samples {
+		y_samples[i] = evaluateVector(coefficients, x)
+
+		// compare to the reference byte at a time implementation
+		y := make([]byte, len(secret))
+		y_samples[i].store(y)
+		c := make([][]byte, len(coefficients))
+		for j := range coefficients {
+			c[j] = make([]byte, len(secret))
+			coefficients[j].store(c[j])
+		}
+		for idx := range secret {
+			// Horner's method with scalar arithmetic
+			expected := add(mult(add(mult(c[2][idx], x), c[1][idx]), x), c[0][idx])
+			if y[idx] != expected {
+				t.Fatalf("Bad: byte %d at %d is %#x, expected %#x", idx, x, y[idx], expected)
+			}
+		}
+	}
+
+	out := make([]byte, len(secret))
+	interpolateVector(x_samples, y_samples, 0).store(out)
+	if !bytes.Equal(out, secret) {
+		t.Fatalf("bad: %v %v", out, secret)
+	}
+}
+
+func TestCombine_KnownAnswer(t *testing.T) {
+	// f(x) = 0x2a + 0x57x
+	parts := [][]byte{
+		{0x2a ^ 0x57, 1},
+		{0x2a ^ 0xae, 2},
+	}
+	secret, err := Combine(parts)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !bytes.Equal(secret, []byte{0x2a}) {
+		t.Fatalf("bad: %v", secret)
+	}
+}
+
+func BenchmarkMult(b *testing.B) {
+	var r uint8
+	for i := 0; i < b.N; i++ {
+		r = mult(r^uint8(i), uint8(i>>8))
+	}
+}
+
+func BenchmarkMult8(b *testing.B) {
+	var r uint64
+	for i := 0; i < b.N; i++ {
+		r = mult8(r^uint64(i), uint8(i>>8))
+	}
+}
+
+func BenchmarkSplit(b *testing.B) {
+	secret := make([]byte, 1024)
+	if _, err := rand.Read(secret); err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	b.SetBytes(int64(len(secret)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Split(secret, 12, 4); err != nil {
+			b.Fatalf("err: %v", err)
+		}
+	}
+}
+
+func BenchmarkCombine(b *testing.B) {
+	secret := make([]byte, 1024)
+	if _, err := rand.Read(secret); err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	parts, err := Split(secret, 12, 4)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	b.SetBytes(int64(len(secret)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Combine(parts[:4]); err != nil {
+			b.Fatalf("err: %v", err)
+		}
+	}
+}
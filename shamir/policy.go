@@ -0,0 +1,287 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+)
+
+// Policy describes an access structure: a group of members, any [Policy.Threshold] of which can recover the group secret. Members can be participants or nested groups, and each member can carry more than one share of its parent group by [Policy.Weight]. Policies combine into AND/OR rules:
+//
+//	// either parent alone, or any three of five children
+//	Any(Participant(), Participant(), Threshold(3, Participants(5)...))
+//
+//	// one shard from each of two houses
+//	All(Any(Participants(3)...), Any(Participants(4)...))
+type Policy struct {
+	// Threshold is the number of member shares required to recover the group secret. Participants have no threshold.
+	Threshold int
+	// Weight is the number of shares this policy holds within its parent group. Zero is treated as one.
+	Weight int
+	// Members are the nested policies of a group. A policy without members is a participant.
+	Members []Policy
+}
+
+// Participant is a [Policy] of a single person holding one share.
+func Participant() Policy {
+	return Policy{Weight: 1}
+}
+
+// Participants creates a list of n participants.
+func Participants(n int) []Policy {
+	p := make([]Policy, n)
+	for i := range p {
+		p[i] = Participant()
+	}
+	return p
+}
+
+// Threshold creates a group [Policy] that recovers its secret from any k member shares.
+func Threshold(k int, members ...Policy) Policy {
+	return Policy{
+		Threshold: k,
+		Weight:    1,
+		Members:   members,
+	}
+}
+
+// Any creates a group [Policy] that any single member can satisfy.
+func Any(members ...Policy) Policy {
+	return Threshold(1, members...)
+}
+
+// All creates a group [Policy] that requires every member.
+func All(members ...Policy) Policy {
+	return Threshold(len(members), members...)
+}
+
+// Weighted assigns a number of shares to a [Policy] within its parent group.
+func Weighted(weight int, p Policy) Policy {
+	p.Weight = weight
+	return p
+}
+
+func (p Policy) weight() int {
+	if p.Weight == 0 {
+		return 1
+	}
+	return p.Weight
+}
+
+// Validate checks that thresholds can be satisfied and that each group fits into the 255 available share coordinates.
+func (p Policy) Validate() error {
+	return p.validate(0)
+}
+
+func (p Policy) validate(depth int) error {
+	if depth > 255 {
+		return errors.New("policy cannot be nested deeper than 255 levels")
+	}
+	if w := p.weight(); w < 1 || w > 255 {
+		return fmt.Errorf("policy weight %d is out of range [1-255]", w)
+	}
+	if len(p.Members) == 0 {
+		if p.Threshold != 0 {
+			return errors.New("participant cannot have a threshold")
+		}
+		if depth == 0 {
+			return errors.New("policy must have at least one member")
+		}
+		return nil
+	}
+
+	total := 0
+	for i, member := range p.Members {
+		if err := member.validate(depth + 1); err != nil {
+			return fmt.Errorf("member %d: %w", i+1, err)
+		}
+		total += member.weight()
+	}
+	if total > 255 {
+		return fmt.Errorf("group shares %d cannot exceed 255", total)
+	}
+	if p.Threshold < 1 || p.Threshold > total {
+		return fmt.Errorf("threshold %d is out of range [1-%d]", p.Threshold, total)
+	}
+	return nil
+}
+
+// Step locates a share within one group of a [Policy].
+type Step struct {
+	// Member is the index of the member within the group.
+	Member uint8
+	// Threshold is the number of shares that recover the group secret.
+	Threshold uint8
+	// Weight is the number of group shares held by the member.
+	Weight uint8
+}
+
+// PolicyShare is the part of a secret held by one [Policy] participant.
+type PolicyShare struct {
+	// Path leads from the top group to the participant.
+	Path []Step
+	// Data holds [Step.Weight] shares of the last group in [Split] format.
+	Data []byte
+}
+
+// SplitPolicy breaks the secret into a share for every participant of the [Policy]. Each group splits its own secret among its members, so a nested group recovers the shares it holds in its parent before the parent can recover its secret.
+func SplitPolicy(secret []byte, p Policy) ([]PolicyShare, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("cannot split an empty secret")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return splitGroup(secret, p, nil)
+}
+
+func splitGroup(secret []byte, p Policy, path []Step) (shares []PolicyShare, err error) {
+	xCoordinates := mathrand.Perm(255)
+	cursor := 0
+	for i, member := range p.Members {
+		w := member.weight()
+		data := make([]byte, 0, w*(len(secret)+1))
+		for j := 0; j < w; j++ {
+			data = append(data, make([]byte, len(secret))...)
+			data = append(data, uint8(xCoordinates[cursor+j])+1)
+		}
+
+		next := make([]Step, len(path), len(path)+1)
+		copy(next, path)
+		next = append(next, Step{
+			Member:    uint8(i),
+			Threshold: uint8(p.Threshold),
+			Weight:    uint8(w),
+		})
+
+		shares = append(shares, PolicyShare{Path: next, Data: data})
+		cursor += w
+	}
+
+	coefficients, err := makeCoefficients(secret, p.Threshold-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate polynomial: %w", err)
+	}
+	defer wipeVectors(coefficients)
+	stride := len(secret) + 1
+	for _, share := range shares {
+		for j := 0; j < len(share.Data); j += stride {
+			x := share.Data[j+stride-1]
+			evaluateVector(coefficients, x).store(share.Data[j : j+stride-1])
+		}
+	}
+
+	result := make([]PolicyShare, 0, len(shares))
+	for i, member := range p.Members {
+		if len(member.Members) == 0 {
+			result = append(result, shares[i])
+			continue
+		}
+		nested, err := splitGroup(shares[i].Data, member, shares[i].Path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nested...)
+	}
+	return result, nil
+}
+
+// CombinePolicy reverses [SplitPolicy] once the shares satisfy the policy. Groups that lack their quorum are skipped, as long as the remaining members are sufficient.
+func CombinePolicy(shares []PolicyShare) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares provided")
+	}
+	return combineGroup(shares, 0)
+}
+
+func combineGroup(shares []PolicyShare, depth int) ([]byte, error) {
+	var (
+		threshold = -1
+		order     []uint8
+		members   = make(map[uint8][]PolicyShare)
+	)
+	for _, share := range shares {
+		if len(share.Path) <= depth {
+			return nil, errors.New("share path is too short")
+		}
+		step := share.Path[depth]
+		if threshold == -1 {
+			threshold = int(step.Threshold)
+		} else if threshold != int(step.Threshold) {
+			return nil, fmt.Errorf("shares disagree on group threshold: %d and %d", threshold, step.Threshold)
+		}
+		if _, ok := members[step.Member]; !ok {
+			order = append(order, step.Member)
+		}
+		members[step.Member] = append(members[step.Member], share)
+	}
+	if threshold < 1 {
+		return nil, errors.New("group threshold must be at least 1")
+	}
+
+	var (
+		x_samples []uint8
+		y_samples []vector
+		checkMap  = map[byte]bool{}
+		lastErr   error
+		secretLen = -1
+	)
+	for _, index := range order {
+		group := members[index]
+		step := group[0].Path[depth]
+		if step.Weight == 0 {
+			return nil, errors.New("member weight must be at least 1")
+		}
+
+		var data []byte
+		if len(group[0].Path) == depth+1 {
+			data = group[0].Data
+			for _, duplicate := range group[1:] {
+				if len(duplicate.Path) != depth+1 || !bytes.Equal(data, duplicate.Data) {
+					return nil, fmt.Errorf("member %d has conflicting shares", index+1)
+				}
+			}
+		} else {
+			var err error
+			if data, err = combineGroup(group, depth+1); err != nil {
+				lastErr = fmt.Errorf("member %d: %w", index+1, err)
+				continue
+			}
+		}
+
+		w := int(step.Weight)
+		if len(data) < 2*w || len(data)%w != 0 {
+			return nil, fmt.Errorf("member %d shares are corrupt", index+1)
+		}
+		stride := len(data) / w
+		if secretLen != -1 && secretLen != stride-1 {
+			return nil, fmt.Errorf("all parts must be the same length")
+		}
+		secretLen = stride - 1
+		for j := 0; j < len(data); j += stride {
+			x := data[j+stride-1]
+			if checkMap[x] {
+				return nil, fmt.Errorf("duplicate part detected")
+			}
+			checkMap[x] = true
+			x_samples = append(x_samples, x)
+			y_samples = append(y_samples, loadVector(data[j:j+stride-1]))
+		}
+	}
+
+	if len(x_samples) < threshold {
+		if lastErr != nil {
+			return nil, fmt.Errorf("collected %d of %d required shares: %w", len(x_samples), threshold, lastErr)
+		}
+		return nil, fmt.Errorf("collected %d of %d required shares", len(x_samples), threshold)
+	}
+	x_samples = x_samples[:threshold]
+	y_samples = y_samples[:threshold]
+
+	secret := make([]byte, secretLen)
+	intercepts := interpolateVector(x_samples, y_samples, 0)
+	intercepts.store(secret)
+	intercepts.wipe()
+	return secret, nil
+}
@@ -0,0 +1,122 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPolicy_invalid(t *testing.T) {
+	cases := []Policy{
+		Participant(),
+		Threshold(0, Participants(3)...),
+		Threshold(4, Participants(3)...),
+		Threshold(2, Weighted(200, Participant()), Weighted(100, Participant())),
+		Any(Policy{Threshold: 1}),
+	}
+	for _, p := range cases {
+		if err := p.Validate(); err == nil {
+			t.Fatalf("expect error: %+v", p)
+		}
+	}
+}
+
+func TestPolicy_ParentOrChildren(t *testing.T) {
+	secret := []byte("test")
+	shares, err := SplitPolicy(secret, Any(
+		Participant(),
+		Participant(),
+		Threshold(3, Participants(5)...),
+	))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(shares) != 7 {
+		t.Fatalf("bad: %v", shares)
+	}
+
+	for _, parent := range shares[:2] {
+		recomb, err := CombinePolicy([]PolicyShare{parent})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if !bytes.Equal(recomb, secret) {
+			t.Fatalf("bad: %v %v", recomb, secret)
+		}
+	}
+
+	recomb, err := CombinePolicy(shares[3:6])
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !bytes.Equal(recomb, secret) {
+		t.Fatalf("bad: %v %v", recomb, secret)
+	}
+
+	if _, err = CombinePolicy(shares[5:7]); err == nil {
+		t.Fatalf("two children should not recover the secret")
+	}
+}
+
+func TestPolicy_Houses(t *testing.T) {
+	secret := []byte("test")
+	shares, err := SplitPolicy(secret, All(
+		Any(Participants(3)...),
+		Any(Participants(2)...),
+	))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if _, err = CombinePolicy(shares[:3]); err == nil {
+		t.Fatalf("one house should not recover the secret")
+	}
+
+	for i := 0; i < 3; i++ {
+		for j := 3; j < 5; j++ {
+			recomb, err := CombinePolicy([]PolicyShare{shares[i], shares[j]})
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if !bytes.Equal(recomb, secret) {
+				t.Fatalf("bad: %v %v", recomb, secret)
+			}
+		}
+	}
+}
+
+func TestPolicy_Weighted(t *testing.T) {
+	secret := []byte("test")
+	shares, err := SplitPolicy(secret, Threshold(
+		3,
+		Weighted(2, Participant()),
+		Weighted(2, Threshold(2, Participants(3)...)),
+		Participant(),
+		Participant(),
+	))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(shares) != 6 {
+		t.Fatalf("bad: %v", shares)
+	}
+
+	cases := [][]PolicyShare{
+		{shares[0], shares[4]},
+		{shares[0], shares[1], shares[2]},
+		{shares[1], shares[3], shares[5]},
+		{shares[4], shares[5], shares[0]},
+	}
+	for _, parts := range cases {
+		recomb, err := CombinePolicy(parts)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if !bytes.Equal(recomb, secret) {
+			t.Fatalf("bad: %v %v", recomb, secret)
+		}
+	}
+
+	if _, err = CombinePolicy([]PolicyShare{shares[1], shares[4]}); err == nil {
+		t.Fatalf("incomplete nested group should not recover the secret")
+	}
+}
@@ -0,0 +1,168 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+)
+
+var (
+	// Mersenne61 is the prime 2^61-1. Secrets are split in blocks of seven bytes, each of which takes eight bytes in a share.
+	Mersenne61 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 61), big.NewInt(1))
+
+	// Mersenne127 is the prime 2^127-1. Secrets are split in blocks of fifteen bytes, each of which takes sixteen bytes in a share.
+	Mersenne127 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+)
+
+// PrimeField is a [Backend] that splits secrets over integers modulo a prime. The secret is padded and cut into blocks that are smaller than the prime, and each block becomes the intercept of a polynomial. The arithmetic relies on [big.Int], which does not run in constant time.
+type PrimeField struct {
+	prime *big.Int
+	block int // secret bytes per polynomial
+	width int // share bytes per polynomial
+}
+
+// NewPrimeField creates a [PrimeField] [Backend] using a prime larger than 2^16.
+func NewPrimeField(prime *big.Int) (*PrimeField, error) {
+	if prime == nil {
+		return nil, errors.New("cannot use a <nil> prime")
+	}
+	if prime.BitLen() <= 16 {
+		return nil, fmt.Errorf("prime %s must be larger than 2^16", prime)
+	}
+	if !prime.ProbablyPrime(32) {
+		return nil, fmt.Errorf("number %s is not a prime", prime)
+	}
+	return &PrimeField{
+		prime: new(big.Int).Set(prime),
+		block: (prime.BitLen() - 1) / 8,
+		width: (prime.BitLen() + 7) / 8,
+	}, nil
+}
+
+// Prime returns a copy of the field modulus.
+func (f *PrimeField) Prime() *big.Int {
+	return new(big.Int).Set(f.prime)
+}
+
+// Split takes an arbitrarily long secret and generates a `parts` number of shares, `threshold` of which are required to reconstruct the secret. The returned shares are formatted as {y1, y2, .., yN, x}, where each y value is a big endian integer that fills the byte width of the prime.
+func (f *PrimeField) Split(secret []byte, parts, threshold int) ([][]byte, error) {
+	if err := validateSplit(secret, parts, threshold); err != nil {
+		return nil, err
+	}
+
+	// Pad the secret with a single 0x80 byte followed by zeros to fill the last block.
+	padded := make([]byte, (len(secret)/f.block+1)*f.block)
+	copy(padded, secret)
+	padded[len(secret)] = 0x80
+	blocks := len(padded) / f.block
+
+	xCoordinates := mathrand.Perm(255)
+	out := make([][]byte, parts)
+	for idx := range out {
+		out[idx] = make([]byte, blocks*f.width+1)
+		out[idx][blocks*f.width] = uint8(xCoordinates[idx]) + 1
+	}
+
+	var (
+		err          error
+		coefficients = make([]*big.Int, threshold)
+		x, y         = new(big.Int), new(big.Int)
+	)
+	for b := 0; b < blocks; b++ {
+		coefficients[0] = new(big.Int).SetBytes(padded[b*f.block : (b+1)*f.block])
+		for i := 1; i < threshold; i++ {
+			if coefficients[i], err = rand.Int(rand.Reader, f.prime); err != nil {
+				return nil, fmt.Errorf("failed to generate polynomial: %w", err)
+			}
+		}
+
+		for i := 0; i < parts; i++ {
+			// Compute the polynomial value using Horner's method.
+			x.SetInt64(int64(xCoordinates[i]) + 1)
+			y.Set(coefficients[threshold-1])
+			for j := threshold - 2; j >= 0; j-- {
+				y.Mul(y, x)
+				y.Add(y, coefficients[j])
+				y.Mod(y, f.prime)
+			}
+			y.FillBytes(out[i][b*f.width : (b+1)*f.width])
+		}
+	}
+	return out, nil
+}
+
+// Combine is used to reverse [PrimeField.Split] and reconstruct a secret once a `threshold` number of parts are available.
+func (f *PrimeField) Combine(parts [][]byte) ([]byte, error) {
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("less than two parts cannot be used to reconstruct the secret")
+	}
+	partLen := len(parts[0])
+	if partLen < f.width+1 || (partLen-1)%f.width != 0 {
+		return nil, fmt.Errorf("parts must be a multiple of %d bytes plus one", f.width)
+	}
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) != partLen {
+			return nil, fmt.Errorf("all parts must be the same length")
+		}
+	}
+
+	// Compute Lagrange basis polynomials at zero for each x coordinate.
+	checkMap := map[byte]bool{}
+	basis := make([]*big.Int, len(parts))
+	for i, part := range parts {
+		xi := part[partLen-1]
+		if xi == 0 {
+			return nil, fmt.Errorf("part %d has zero x coordinate", i+1)
+		}
+		if checkMap[xi] {
+			return nil, fmt.Errorf("duplicate part detected")
+		}
+		checkMap[xi] = true
+
+		num, denom := big.NewInt(1), big.NewInt(1)
+		for j, other := range parts {
+			if i == j {
+				continue
+			}
+			xj := big.NewInt(int64(other[partLen-1]))
+			num.Mul(num, xj)
+			denom.Mul(denom, xj.Sub(xj, big.NewInt(int64(xi))))
+		}
+		denom.Mod(denom, f.prime)
+		if denom.ModInverse(denom, f.prime) == nil {
+			return nil, fmt.Errorf("part %d cannot be interpolated", i+1)
+		}
+		basis[i] = num.Mul(num, denom).Mod(num, f.prime)
+	}
+
+	blocks := (partLen - 1) / f.width
+	padded := make([]byte, blocks*f.block)
+	y, term := new(big.Int), new(big.Int)
+	for b := 0; b < blocks; b++ {
+		y.SetInt64(0)
+		for i, part := range parts {
+			term.SetBytes(part[b*f.width : (b+1)*f.width])
+			if term.Cmp(f.prime) >= 0 {
+				return nil, fmt.Errorf("part %d is outside of the prime field", i+1)
+			}
+			y.Add(y, term.Mul(term, basis[i]))
+		}
+		y.Mod(y, f.prime)
+		if y.BitLen() > f.block*8 {
+			return nil, errors.New("parts are corrupt or belong to different secrets")
+		}
+		y.FillBytes(padded[b*f.block : (b+1)*f.block])
+	}
+
+	// Remove the padding.
+	end := len(padded) - 1
+	for end >= 0 && padded[end] == 0 {
+		end--
+	}
+	if end < 0 || padded[end] != 0x80 {
+		return nil, errors.New("parts are corrupt or belong to different secrets")
+	}
+	return padded[:end], nil
+}
@@ -0,0 +1,71 @@
+package shamir
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestPrimeField_invalid(t *testing.T) {
+	for _, prime := range []*big.Int{nil, big.NewInt(65521), big.NewInt(1 << 20)} {
+		if _, err := NewPrimeField(prime); err == nil {
+			t.Fatalf("expect error: %v", prime)
+		}
+	}
+}
+
+func TestPrimeField_Combine(t *testing.T) {
+	for _, prime := range []*big.Int{big.NewInt(65537), Mersenne61, Mersenne127} {
+		f, err := NewPrimeField(prime)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		for _, secret := range [][]byte{
+			[]byte("t"),
+			[]byte("test"),
+			[]byte("fifteen bytes.."),
+			{0, 0, 0x80, 0},
+		} {
+			out, err := f.Split(secret, 5, 3)
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			for _, share := range out {
+				if (len(share)-1)%f.width != 0 {
+					t.Fatalf("bad share length: %d", len(share))
+				}
+			}
+
+			for i := 0; i < 3; i++ {
+				recomb, err := f.Combine(out[i : i+3])
+				if err != nil {
+					t.Fatalf("err: %v", err)
+				}
+				if !bytes.Equal(recomb, secret) {
+					t.Fatalf("bad: %v %v", recomb, secret)
+				}
+			}
+		}
+	}
+}
+
+func TestPrimeField_Corrupt(t *testing.T) {
+	f, err := NewPrimeField(Mersenne61)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	out, err := f.Split([]byte("test"), 3, 2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if _, err = f.Combine([][]byte{out[0], out[0]}); err == nil {
+		t.Fatalf("duplicate parts should err")
+	}
+
+	out[1] = bytes.Repeat([]byte{0xff}, len(out[1]))
+	if _, err = f.Combine(out[:2]); err == nil {
+		t.Fatalf("part outside of the field should err")
+	}
+}
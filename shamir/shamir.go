@@ -7,7 +7,6 @@ Package shamir implements Shamir Secret Sharing (SSS) scheme that splits a passw
 package shamir
 
 import (
-	"crypto/rand"
 	"crypto/subtle"
 	"fmt"
 	mathrand "math/rand"
@@ -21,69 +20,6 @@ const (
 	ShareOverhead = 1
 )
 
-// polynomial represents a polynomial of arbitrary degree
-type polynomial struct {
-	coefficients []uint8
-}
-
-// makePolynomial constructs a random polynomial of the given
-// degree but with the provided intercept value.
-func makePolynomial(intercept, degree uint8) (polynomial, error) {
-	// Create a wrapper
-	p := polynomial{
-		coefficients: make([]byte, degree+1),
-	}
-
-	// Ensure the intercept is set
-	p.coefficients[0] = intercept
-
-	// Assign random co-efficients to the polynomial
-	if _, err := rand.Read(p.coefficients[1:]); err != nil {
-		return p, err
-	}
-
-	return p, nil
-}
-
-// evaluate returns the value of the polynomial for the given x
-func (p *polynomial) evaluate(x uint8) uint8 {
-	// Special case the origin
-	if x == 0 {
-		return p.coefficients[0]
-	}
-
-	// Compute the polynomial value using Horner's method.
-	degree := len(p.coefficients) - 1
-	out := p.coefficients[degree]
-	for i := degree - 1; i >= 0; i-- {
-		coeff := p.coefficients[i]
-		out = add(mult(out, x), coeff)
-	}
-	return out
-}
-
-// interpolatePolynomial takes N sample points and returns
-// the value at a given x using a lagrange interpolation.
-func interpolatePolynomial(x_samples, y_samples []uint8, x uint8) uint8 {
-	limit := len(x_samples)
-	var result, basis uint8
-	for i := 0; i < limit; i++ {
-		basis = 1
-		for j := 0; j < limit; j++ {
-			if i == j {
-				continue
-			}
-			num := add(x, x_samples[j])
-			denom := add(x_samples[i], x_samples[j])
-			term := div(num, denom)
-			basis = mult(basis, term)
-		}
-		group := mult(y_samples[i], basis)
-		result = add(result, group)
-	}
-	return result
-}
-
 // div divides two numbers in GF(2^8)
 func div(a, b uint8) uint8 {
 	if b == 0 {
@@ -140,26 +76,71 @@ func add(a, b uint8) uint8 {
 // than 256. The returned shares are each one byte longer than the secret
 // as they attach a tag used to reconstruct the secret.
 func Split(secret []byte, parts, threshold int) ([][]byte, error) {
+	if err := validateSplit(secret, parts, threshold); err != nil {
+		return nil, err
+	}
+
+	// Generate random list of x coordinates
+	mathrand.Seed(time.Now().UnixNano())
+	xCoordinates := mathrand.Perm(255)
+	return split(secret, xCoordinates[:parts], threshold)
+}
+
+// SplitSequential works like [Split], but assigns x coordinates 1 to `parts`
+// in order instead of picking them at random. The tag of each share then
+// matches its position, so shares can be numbered on paper and
+// the numbers checked against the shares using [Index].
+func SplitSequential(secret []byte, parts, threshold int) ([][]byte, error) {
+	if err := validateSplit(secret, parts, threshold); err != nil {
+		return nil, err
+	}
+
+	xCoordinates := make([]int, parts)
+	for i := range xCoordinates {
+		xCoordinates[i] = i
+	}
+	return split(secret, xCoordinates, threshold)
+}
+
+// Index returns the x coordinate tag of a share.
+func Index(share []byte) (uint8, error) {
+	if len(share) < 2 {
+		return 0, fmt.Errorf("parts must be at least two bytes")
+	}
+	return share[len(share)-1], nil
+}
+
+func validateSplit(secret []byte, parts, threshold int) error {
+	if err := validateThreshold(parts, threshold); err != nil {
+		return err
+	}
+	if len(secret) == 0 {
+		return fmt.Errorf("cannot split an empty secret")
+	}
+	return nil
+}
+
+func validateThreshold(parts, threshold int) error {
 	// Sanity check the input
 	if parts < threshold {
-		return nil, fmt.Errorf("parts cannot be less than threshold")
+		return fmt.Errorf("parts cannot be less than threshold")
 	}
 	if parts > 255 {
-		return nil, fmt.Errorf("parts cannot exceed 255")
+		return fmt.Errorf("parts cannot exceed 255")
 	}
 	if threshold < 2 {
-		return nil, fmt.Errorf("threshold must be at least 2")
+		return fmt.Errorf("threshold must be at least 2")
 	}
 	if threshold > 255 {
-		return nil, fmt.Errorf("threshold cannot exceed 255")
-	}
-	if len(secret) == 0 {
-		return nil, fmt.Errorf("cannot split an empty secret")
+		return fmt.Errorf("threshold cannot exceed 255")
 	}
+	return nil
+}
 
-	// Generate random list of x coordinates
-	mathrand.Seed(time.Now().UnixNano())
-	xCoordinates := mathrand.Perm(255)
+// split generates a share for each x coordinate, which is offset by one
+// to avoid revealing the secret at zero.
+func split(secret []byte, xCoordinates []int, threshold int) ([][]byte, error) {
+	parts := len(xCoordinates)
 
 	// Allocate the output array, initialize the final byte
 	// of the output with the offset. The representation of each
@@ -173,27 +154,41 @@ func Split(secret []byte, parts, threshold int) ([][]byte, error) {
 	// Construct a random polynomial for each byte of the secret.
 	// Because we are using a field of size 256, we can only represent
 	// a single byte as the intercept of the polynomial, so we must
-	// use a new polynomial for each byte.
-	for idx, val := range secret {
-		p, err := makePolynomial(val, uint8(threshold-1))
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate polynomial: %w", err)
-		}
+	// use a new polynomial for each byte. All the polynomials are
+	// evaluated together, eight bytes at a time.
+	coefficients, err := makeCoefficients(secret, threshold-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate polynomial: %w", err)
+	}
+	defer wipeVectors(coefficients)
 
-		// Generate a `parts` number of (x,y) pairs
-		// We cheat by encoding the x value once as the final index,
-		// so that it only needs to be stored once.
-		for i := 0; i < parts; i++ {
-			x := uint8(xCoordinates[i]) + 1
-			y := p.evaluate(x)
-			out[i][idx] = y
-		}
+	// Generate a `parts` number of (x,y) pairs
+	// We cheat by encoding the x value once as the final index,
+	// so that it only needs to be stored once.
+	for i := 0; i < parts; i++ {
+		x := uint8(xCoordinates[i]) + 1
+		evaluateVector(coefficients, x).store(out[i][:len(secret)])
 	}
 
 	// Return the encoded secrets
 	return out, nil
 }
 
+// makeCoefficients constructs random polynomials of the given degree
+// for each byte of the secret, which becomes the intercept.
+func makeCoefficients(secret []byte, degree int) ([]vector, error) {
+	coefficients := make([]vector, degree+1)
+	coefficients[0] = loadVector(secret)
+	for i := 1; i <= degree; i++ {
+		random, err := randomVector(len(secret))
+		if err != nil {
+			return nil, err
+		}
+		coefficients[i] = random
+	}
+	return coefficients, nil
+}
+
 // Combine is used to reverse a Split and reconstruct a secret
 // once a `threshold` number of parts are available.
 func Combine(parts [][]byte) ([]byte, error) {
@@ -218,7 +213,7 @@ func Combine(parts [][]byte) ([]byte, error) {
 
 	// Buffer to store the samples
 	x_samples := make([]uint8, len(parts))
-	y_samples := make([]uint8, len(parts))
+	y_samples := make([]vector, len(parts))
 
 	// Set the x value for each sample and ensure no x_sample values are the same,
 	// otherwise div() can be unhappy
@@ -230,20 +225,13 @@ func Combine(parts [][]byte) ([]byte, error) {
 		}
 		checkMap[samp] = true
 		x_samples[i] = samp
+		y_samples[i] = loadVector(part[:firstPartLen-1])
 	}
 
-	// Reconstruct each byte
-	for idx := range secret {
-		// Set the y value for each sample
-		for i, part := range parts {
-			y_samples[i] = part[idx]
-		}
-
-		// Interpolate the polynomial and compute the value at 0
-		val := interpolatePolynomial(x_samples, y_samples, 0)
-
-		// Evaluate the 0th value to get the intercept
-		secret[idx] = val
-	}
+	// Interpolate the polynomials and compute their values at 0
+	// to reconstruct every byte of the secret
+	intercepts := interpolateVector(x_samples, y_samples, 0)
+	intercepts.store(secret)
+	intercepts.wipe()
 	return secret, nil
 }
@@ -156,46 +156,75 @@ func TestField_Divide(t *testing.T) {
 	}
 }
 
-func TestPolynomial_Random(t *testing.T) {
-	p, err := makePolynomial(42, 2)
+func TestCoefficients_Random(t *testing.T) {
+	coefficients, err := makeCoefficients([]byte{42}, 2)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	if p.coefficients[0] != 42 {
-		t.Fatalf("bad: %v", p.coefficients)
+	if len(coefficients) != 3 || coefficients[0][0] != 42 {
+		t.Fatalf("bad: %v", coefficients)
 	}
 }
 
-func TestPolynomial_Eval(t *testing.T) {
-	p, err := makePolynomial(42, 1)
+func TestCoefficients_Eval(t *testing.T) {
+	coefficients, err := makeCoefficients([]byte{42}, 1)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
-	if out := p.evaluate(0); out != 42 {
+	if out := evaluateVector(coefficients, 0); out[0] != 42 {
 		t.Fatalf("bad: %v", out)
 	}
 
-	out := p.evaluate(1)
-	exp := add(42, mult(1, p.coefficients[1]))
-	if out != exp {
-		t.Fatalf("bad: %v %v %v", out, exp, p.coefficients)
+	out := evaluateVector(coefficients, 1)
+	exp := uint64(add(42, mult(1, uint8(coefficients[1][0]))))
+	if out[0] != exp {
+		t.Fatalf("bad: %v %v %v", out, exp, coefficients)
 	}
 }
 
 func TestInterpolate_Rand(t *testing.T) {
 	for i := 0; i < 256; i++ {
-		p, err := makePolynomial(uint8(i), 2)
+		coefficients, err := makeCoefficients([]byte{uint8(i)}, 2)
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
 
 		x_vals := []uint8{1, 2, 3}
-		y_vals := []uint8{p.evaluate(1), p.evaluate(2), p.evaluate(3)}
-		out := interpolatePolynomial(x_vals, y_vals, 0)
+		var out uint8
+		for j, basis := range lagrangeBasis(x_vals, 0) {
+			out ^= mult(uint8(evaluateVector(coefficients, x_vals[j])[0]), basis)
+		}
 		if out != uint8(i) {
 			t.Fatalf("Bad: %v %d", out, i)
 		}
 	}
 }
+
+func TestSplitSequential(t *testing.T) {
+	secret := []byte("test")
+
+	out, err := SplitSequential(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i, share := range out {
+		index, err := Index(share)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if int(index) != i+1 {
+			t.Fatalf("bad index: %d %d", index, i+1)
+		}
+	}
+
+	recomb, err := Combine(out[2:])
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !bytes.Equal(recomb, secret) {
+		t.Fatalf("bad: %v %v", recomb, secret)
+	}
+}
@@ -0,0 +1,127 @@
+package shamir
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SLIP-0039 sharing scheme constants.
+const (
+	slip39DigestLength = 4
+	slip39DigestIndex  = 254
+	slip39SecretIndex  = 255
+)
+
+// SplitSLIP39 splits the secret using the sharing scheme of SLIP-0039, which differs from [Split]. The x coordinates are member indexes counted from zero, the secret is the value of the polynomial at 255, and a digest of the secret is the value at 254, which lets [CombineSLIP39] reject corrupt shares. The returned shares use the {y1, y2, .., yN, x} layout of [Split]. A threshold of one returns copies of the secret.
+//
+// See https://github.com/satoshilabs/slips/blob/master/slip-0039.md#shamirs-secret-sharing
+func SplitSLIP39(secret []byte, parts, threshold int) ([][]byte, error) {
+	return splitSLIP39(rand.Reader, secret, parts, threshold)
+}
+
+func splitSLIP39(random io.Reader, secret []byte, parts, threshold int) ([][]byte, error) {
+	if threshold < 1 || threshold > parts {
+		return nil, fmt.Errorf("threshold %d is out of range [1-%d]", threshold, parts)
+	}
+	if parts > slip39DigestIndex {
+		return nil, fmt.Errorf("parts cannot exceed %d", slip39DigestIndex)
+	}
+	if len(secret) <= slip39DigestLength {
+		return nil, fmt.Errorf("secret must be longer than %d bytes", slip39DigestLength)
+	}
+
+	out := make([][]byte, parts)
+	for idx := range out {
+		out[idx] = make([]byte, len(secret)+1)
+		out[idx][len(secret)] = uint8(idx)
+	}
+	if threshold == 1 {
+		for _, share := range out {
+			copy(share, secret)
+		}
+		return out, nil
+	}
+
+	// The first threshold-2 shares are random. Together with the digest and
+	// the secret they define the polynomial that yields the remaining shares.
+	x_samples := make([]uint8, 0, threshold)
+	y_samples := make([]vector, 0, threshold)
+	for idx := 0; idx < threshold-2; idx++ {
+		if _, err := io.ReadFull(random, out[idx][:len(secret)]); err != nil {
+			return nil, fmt.Errorf("failed to generate share: %w", err)
+		}
+		x_samples = append(x_samples, uint8(idx))
+		y_samples = append(y_samples, loadVector(out[idx][:len(secret)]))
+	}
+
+	digest := make([]byte, len(secret))
+	if _, err := io.ReadFull(random, digest[slip39DigestLength:]); err != nil {
+		return nil, fmt.Errorf("failed to generate digest: %w", err)
+	}
+	copy(digest, slip39Digest(digest[slip39DigestLength:], secret))
+	x_samples = append(x_samples, slip39DigestIndex, slip39SecretIndex)
+	y_samples = append(y_samples, loadVector(digest), loadVector(secret))
+	defer wipeVectors(y_samples)
+
+	for idx := threshold - 2; idx < parts; idx++ {
+		interpolateVector(x_samples, y_samples, uint8(idx)).store(out[idx][:len(secret)])
+	}
+	return out, nil
+}
+
+// CombineSLIP39 reverses [SplitSLIP39] using a `threshold` number of parts and checks the recovered secret against its digest.
+func CombineSLIP39(parts [][]byte, threshold int) ([]byte, error) {
+	if threshold < 1 {
+		return nil, errors.New("threshold must be at least 1")
+	}
+	if len(parts) < threshold {
+		return nil, fmt.Errorf("%d parts cannot satisfy threshold %d", len(parts), threshold)
+	}
+	parts = parts[:threshold]
+
+	partLen := len(parts[0])
+	if partLen <= slip39DigestLength+1 {
+		return nil, fmt.Errorf("parts must be longer than %d bytes", slip39DigestLength+1)
+	}
+	x_samples := make([]uint8, len(parts))
+	y_samples := make([]vector, len(parts))
+	checkMap := map[byte]bool{}
+	for i, part := range parts {
+		if len(part) != partLen {
+			return nil, fmt.Errorf("all parts must be the same length")
+		}
+		x := part[partLen-1]
+		if checkMap[x] {
+			return nil, fmt.Errorf("duplicate part detected")
+		}
+		checkMap[x] = true
+		x_samples[i] = x
+		y_samples[i] = loadVector(part[:partLen-1])
+	}
+
+	secret := make([]byte, partLen-1)
+	if threshold == 1 {
+		copy(secret, parts[0])
+		return secret, nil
+	}
+	intercepts := interpolateVector(x_samples, y_samples, slip39SecretIndex)
+	intercepts.store(secret)
+	intercepts.wipe()
+
+	digest := make([]byte, partLen-1)
+	interpolateVector(x_samples, y_samples, slip39DigestIndex).store(digest)
+	if !hmac.Equal(digest[:slip39DigestLength], slip39Digest(digest[slip39DigestLength:], secret)) {
+		return nil, errors.New("invalid digest of the shared secret")
+	}
+	return secret, nil
+}
+
+func slip39Digest(random, secret []byte) []byte {
+	mac := hmac.New(sha256.New, random)
+	_, _ = mac.Write(secret)
+	return mac.Sum(nil)[:slip39DigestLength]
+}
@@ -0,0 +1,172 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+)
+
+// streamChunkSize is the number of secret bytes that share a set of random polynomials.
+const streamChunkSize = 4096
+
+// Splitter is an [io.Writer] that splits everything written into it into share streams, `threshold` of which are required to reconstruct the secret using [Combiner]. Unlike [Split], each share stream begins with its one byte x coordinate tag, so that the streams can be written without knowing the length of the secret in advance.
+type Splitter struct {
+	shares       []io.Writer
+	threshold    int
+	xCoordinates []uint8
+	buffer       []byte
+}
+
+// NewSplitter writes x coordinate tags to each share stream and returns a [Splitter] ready to take the secret.
+func NewSplitter(shares []io.Writer, threshold int) (*Splitter, error) {
+	if err := validateThreshold(len(shares), threshold); err != nil {
+		return nil, err
+	}
+
+	perm := mathrand.Perm(255)
+	s := &Splitter{
+		shares:       shares,
+		threshold:    threshold,
+		xCoordinates: make([]uint8, len(shares)),
+		buffer:       make([]byte, streamChunkSize),
+	}
+	for i, w := range shares {
+		if w == nil {
+			return nil, fmt.Errorf("share stream %d is <nil>", i+1)
+		}
+		s.xCoordinates[i] = uint8(perm[i]) + 1
+		if _, err := w.Write(s.xCoordinates[i : i+1]); err != nil {
+			return nil, fmt.Errorf("cannot write share stream %d: %w", i+1, err)
+		}
+	}
+	return s, nil
+}
+
+// Write splits p and writes the matching y values into each share stream.
+func (s *Splitter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > streamChunkSize {
+			chunk = chunk[:streamChunkSize]
+		}
+		coefficients, err := makeCoefficients(chunk, s.threshold-1)
+		if err != nil {
+			return n, fmt.Errorf("failed to generate polynomial: %w", err)
+		}
+		y := s.buffer[:len(chunk)]
+		for i, w := range s.shares {
+			evaluateVector(coefficients, s.xCoordinates[i]).store(y)
+			if _, err = w.Write(y); err != nil {
+				wipeVectors(coefficients)
+				return n, fmt.Errorf("cannot write share stream %d: %w", i+1, err)
+			}
+		}
+		wipeVectors(coefficients)
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+// ReadFrom splits the secret from [io.Reader] until [io.EOF].
+func (s *Splitter) ReadFrom(r io.Reader) (n int64, err error) {
+	chunk := make([]byte, streamChunkSize)
+	for {
+		m, err := io.ReadFull(r, chunk)
+		if m > 0 {
+			if _, werr := s.Write(chunk[:m]); werr != nil {
+				return n, werr
+			}
+			n += int64(m)
+		}
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			return n, nil
+		default:
+			return n, err
+		}
+	}
+}
+
+// Combiner is an [io.Reader] that reconstructs the secret from share streams created by [Splitter].
+type Combiner struct {
+	shares  []io.Reader
+	basis   []uint8
+	samples [][]byte
+	done    bool
+}
+
+// NewCombiner reads x coordinate tags from each share stream and returns a [Combiner] ready to recover the secret. Provide at least `threshold` share streams: the secret comes out garbled otherwise, because share streams carry no integrity checks.
+func NewCombiner(shares []io.Reader) (*Combiner, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("less than two parts cannot be used to reconstruct the secret")
+	}
+
+	x_samples := make([]uint8, len(shares))
+	checkMap := map[byte]bool{}
+	tag := make([]byte, 1)
+	for i, r := range shares {
+		if r == nil {
+			return nil, fmt.Errorf("share stream %d is <nil>", i+1)
+		}
+		if _, err := io.ReadFull(r, tag); err != nil {
+			return nil, fmt.Errorf("cannot read share stream %d tag: %w", i+1, err)
+		}
+		if checkMap[tag[0]] {
+			return nil, fmt.Errorf("duplicate part detected")
+		}
+		checkMap[tag[0]] = true
+		x_samples[i] = tag[0]
+	}
+
+	c := &Combiner{
+		shares:  shares,
+		basis:   lagrangeBasis(x_samples, 0),
+		samples: make([][]byte, len(shares)),
+	}
+	for i := range c.samples {
+		c.samples[i] = make([]byte, streamChunkSize)
+	}
+	return c, nil
+}
+
+// Read reconstructs up to len(p) bytes of the secret.
+func (c *Combiner) Read(p []byte) (n int, err error) {
+	if c.done {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if len(p) > streamChunkSize {
+		p = p[:streamChunkSize]
+	}
+
+	n = -1
+	for i, r := range c.shares {
+		m, err := io.ReadFull(r, c.samples[i][:len(p)])
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			c.done = true
+		default:
+			return 0, fmt.Errorf("cannot read share stream %d: %w", i+1, err)
+		}
+		if n != -1 && n != m {
+			return 0, errors.New("share streams have different lengths")
+		}
+		n = m
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+
+	out := make(vector, (n+7)/8)
+	for i, basis := range c.basis {
+		out.mulAdd(loadVector(c.samples[i][:n]), basis)
+	}
+	out.store(p[:n])
+	return n, nil
+}
@@ -0,0 +1,110 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+)
+
+func TestSplitter_invalid(t *testing.T) {
+	if _, err := NewSplitter([]io.Writer{&bytes.Buffer{}}, 2); err == nil {
+		t.Fatalf("expect error")
+	}
+	if _, err := NewSplitter([]io.Writer{&bytes.Buffer{}, nil}, 2); err == nil {
+		t.Fatalf("expect error")
+	}
+}
+
+func TestCombiner_invalid(t *testing.T) {
+	if _, err := NewCombiner([]io.Reader{bytes.NewReader([]byte{1, 2})}); err == nil {
+		t.Fatalf("expect error")
+	}
+	if _, err := NewCombiner([]io.Reader{
+		bytes.NewReader([]byte{1, 2}),
+		bytes.NewReader([]byte{1, 3}),
+	}); err == nil {
+		t.Fatalf("duplicate parts should err")
+	}
+
+	c, err := NewCombiner([]io.Reader{
+		bytes.NewReader([]byte{1, 2, 3}),
+		bytes.NewReader([]byte{2, 3}),
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err = io.ReadAll(c); err == nil {
+		t.Fatalf("mismatched lengths should err")
+	}
+}
+
+func TestStream(t *testing.T) {
+	for _, size := range []int{0, 1, streamChunkSize, streamChunkSize*3 + 5} {
+		secret := make([]byte, size)
+		if _, err := rand.Read(secret); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		buffers := make([]*bytes.Buffer, 5)
+		writers := make([]io.Writer, len(buffers))
+		for i := range buffers {
+			buffers[i] = &bytes.Buffer{}
+			writers[i] = buffers[i]
+		}
+		s, err := NewSplitter(writers, 3)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if _, err = io.Copy(s, bytes.NewReader(secret)); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			readers := []io.Reader{
+				bytes.NewReader(buffers[i].Bytes()),
+				bytes.NewReader(buffers[i+1].Bytes()),
+				bytes.NewReader(buffers[i+2].Bytes()),
+			}
+			c, err := NewCombiner(readers)
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if n, err := c.Read(nil); n != 0 || err != nil {
+				t.Fatalf("bad: empty read returned %d, %v", n, err)
+			}
+			recomb, err := io.ReadAll(c)
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if !bytes.Equal(recomb, secret) {
+				t.Fatalf("bad: %d bytes do not match", size)
+			}
+		}
+	}
+}
+
+func TestStream_CompatibleWithCombine(t *testing.T) {
+	secret := []byte("test")
+	a, b := &bytes.Buffer{}, &bytes.Buffer{}
+	s, err := NewSplitter([]io.Writer{a, b}, 2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err = s.Write(secret); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// move the tag to the end to get the [Split] format
+	parts := [][]byte{
+		append(a.Bytes()[1:], a.Bytes()[0]),
+		append(b.Bytes()[1:], b.Bytes()[0]),
+	}
+	recomb, err := Combine(parts)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !bytes.Equal(recomb, secret) {
+		t.Fatalf("bad: %v %v", recomb, secret)
+	}
+}
@@ -0,0 +1,29 @@
+package shamir
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ParseVaultKey decodes an unseal key printed by `vault operator init` in either base64 or hex encoding. HashiCorp Vault splits its root key using the same algorithm as [Split], so the decoded key can be combined with other shares by [Combine].
+func ParseVaultKey(key string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if share, err := hex.DecodeString(key); err == nil && len(share) > 1 {
+		return share, nil
+	}
+	share, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.New("unseal key is neither hex nor base64 encoded")
+	}
+	if len(share) < 2 {
+		return nil, errors.New("unseal key must be at least two bytes")
+	}
+	return share, nil
+}
+
+// FormatVaultKey encodes a share created by [Split] as base64, the way `vault operator init` prints unseal keys.
+func FormatVaultKey(share []byte) string {
+	return base64.StdEncoding.EncodeToString(share)
+}
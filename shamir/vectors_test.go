@@ -0,0 +1,142 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// testVectors are published in `testdata/vectors.json`. They were computed by an independent implementation of GF(2^8) arithmetic.
+type testVectors struct {
+	Vault []struct {
+		Description  string
+		Secret       string
+		Threshold    int
+		X            []uint8
+		Coefficients []string
+		Shares       []string
+	}
+	SLIP39 []struct {
+		Description string
+		Secret      string
+		Threshold   int
+		Parts       int
+		Random      string
+		Shares      []string
+	}
+}
+
+func loadTestVectors(t *testing.T) *testVectors {
+	t.Helper()
+	b, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	v := &testVectors{}
+	if err = json.Unmarshal(b, v); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	return v
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	return b
+}
+
+func TestVectors_Vault(t *testing.T) {
+	for _, v := range loadTestVectors(t).Vault {
+		t.Run(v.Description, func(t *testing.T) {
+			secret := mustDecodeHex(t, v.Secret)
+			coefficients := []vector{loadVector(secret)}
+			for _, c := range v.Coefficients {
+				coefficients = append(coefficients, loadVector(mustDecodeHex(t, c)))
+			}
+
+			parts := make([][]byte, len(v.Shares))
+			for i, x := range v.X {
+				expected := mustDecodeHex(t, v.Shares[i])
+				share := make([]byte, len(secret)+1)
+				evaluateVector(coefficients, x).store(share)
+				share[len(secret)] = x
+				if !bytes.Equal(share, expected) {
+					t.Fatalf("share %d: %x, expected %x", i+1, share, expected)
+				}
+
+				parts[i], _ = ParseVaultKey(FormatVaultKey(expected))
+				if !bytes.Equal(parts[i], expected) {
+					t.Fatalf("unseal key %d does not round trip", i+1)
+				}
+			}
+
+			for i := 0; i+v.Threshold <= len(parts); i++ {
+				recomb, err := Combine(parts[i : i+v.Threshold])
+				if err != nil {
+					t.Fatalf("err: %v", err)
+				}
+				if !bytes.Equal(recomb, secret) {
+					t.Fatalf("bad: %x %x", recomb, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestVectors_SLIP39(t *testing.T) {
+	for _, v := range loadTestVectors(t).SLIP39 {
+		t.Run(v.Description, func(t *testing.T) {
+			secret := mustDecodeHex(t, v.Secret)
+			random := bytes.NewReader(mustDecodeHex(t, v.Random))
+			parts, err := splitSLIP39(random, secret, v.Parts, v.Threshold)
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if len(parts) != len(v.Shares) {
+				t.Fatalf("bad: %d shares, expected %d", len(parts), len(v.Shares))
+			}
+			for i, share := range parts {
+				if expected := mustDecodeHex(t, v.Shares[i]); !bytes.Equal(share, expected) {
+					t.Fatalf("share %d: %x, expected %x", i+1, share, expected)
+				}
+			}
+
+			for i := 0; i+v.Threshold <= len(parts); i++ {
+				recomb, err := CombineSLIP39(parts[i:i+v.Threshold], v.Threshold)
+				if err != nil {
+					t.Fatalf("err: %v", err)
+				}
+				if !bytes.Equal(recomb, secret) {
+					t.Fatalf("bad: %x %x", recomb, secret)
+				}
+			}
+
+			if v.Threshold > 1 {
+				parts[0][0] ^= 1
+				if _, err = CombineSLIP39(parts, v.Threshold); err == nil {
+					t.Fatalf("corrupt share should fail the digest check")
+				}
+			}
+		})
+	}
+}
+
+func TestParseVaultKey(t *testing.T) {
+	for _, key := range []string{"2501", "JQE=", " JQE=\n"} {
+		share, err := ParseVaultKey(key)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if !bytes.Equal(share, []byte{0x25, 0x01}) {
+			t.Fatalf("bad: %x", share)
+		}
+	}
+	if _, err := ParseVaultKey("not a key!"); err == nil {
+		t.Fatalf("expect error")
+	}
+}
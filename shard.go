@@ -2,6 +2,9 @@ package kidwords
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"math"
 	"strings"
@@ -79,20 +82,144 @@ func (s Shards) String() string {
 	return b.String()
 }
 
+// Split breaks the key into a number of Kid Words shards using Shamir's Secret Sharing algorithm. Any quorum of shards recovers the key using [Combine]. A string cannot be wiped, so use [SplitBytes] for keys that must not linger in memory.
 func Split(
 	key string,
 	total,
 	quorum int,
-	withOptions ...WriterOption,
+	withOptions ...SplitOption,
 ) (shards Shards, err error) {
-	raw, err := shamir.Split([]byte(key), total, quorum)
+	return SplitContext(context.Background(), key, total, quorum, withOptions...)
+}
+
+// SplitContext works like [Split], but stops when the context is done, which matters for [WithTimeLock] option that takes a while.
+func SplitContext(
+	ctx context.Context,
+	key string,
+	total,
+	quorum int,
+	withOptions ...SplitOption,
+) (shards Shards, err error) {
+	secret := Secret(key)
+	defer secret.Wipe()
+	return SplitBytesContext(ctx, secret, total, quorum, withOptions...)
+}
+
+// SplitBytes works like [Split], but takes the key as bytes, which the caller can wipe afterwards, unlike a string. The key is not retained.
+func SplitBytes(
+	key []byte,
+	total,
+	quorum int,
+	withOptions ...SplitOption,
+) (shards Shards, err error) {
+	return SplitBytesContext(context.Background(), key, total, quorum, withOptions...)
+}
+
+// SplitBytesContext works like [SplitBytes], but stops when the context is done.
+func SplitBytesContext(
+	ctx context.Context,
+	key []byte,
+	total,
+	quorum int,
+	withOptions ...SplitOption,
+) (shards Shards, err error) {
+	o, err := newSplitOptions(withOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer o.wipe()
+
+	secret, flags, err := o.seal(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	backend := backendID(o.backend)
+	var raw [][]byte
+	switch {
+	case o.sequential && backend != backendGF256:
+		return nil, errors.New("sequential shard numbers are only supported by the default secret sharing backend")
+	case o.sequential:
+		raw, err = shamir.SplitSequential(secret, total, quorum)
+	case backend != backendGF256:
+		raw, err = o.backend.Split(secret, total, quorum)
+	default:
+		raw, err = shamir.Split(secret, total, quorum)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer wipeAll(raw)
+	shards = make([]string, len(raw))
+
+	for i, shard := range raw {
+		e := &envelope{flags: flags | envelopeThreshold, threshold: uint8(quorum), payload: shard}
+		if o.sequential {
+			e.flags |= envelopeSequential
+		}
+		if backend != backendGF256 {
+			e.flags |= envelopeBackend
+			e.backend = backend
+			e.digest = backendDigest(o.backend)
+		}
+		encoded, err := encodeShard(e, o.writer...)
+		if err != nil {
+			return nil, err
+		}
+		shards[i] = encoded
+	}
+
+	return shards, nil
+}
+
+// SplitPolicy breaks the key into Kid Words shards, one for each participant of the [shamir.Policy]. Each shard records the group it belongs to, so that [Combine] can recover the key from any set of shards that satisfies the policy.
+func SplitPolicy(
+	key string,
+	policy shamir.Policy,
+	withOptions ...SplitOption,
+) (shards Shards, err error) {
+	return SplitPolicyContext(context.Background(), key, policy, withOptions...)
+}
+
+// SplitPolicyContext works like [SplitPolicy], but stops when the context is done.
+func SplitPolicyContext(
+	ctx context.Context,
+	keyString string,
+	policy shamir.Policy,
+	withOptions ...SplitOption,
+) (shards Shards, err error) {
+	o, err := newSplitOptions(withOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer o.wipe()
+	if o.sequential {
+		return nil, errors.New("sequential shard numbers are not supported by access policies")
+	}
+	key := Secret(keyString)
+	defer key.Wipe()
+	if backendID(o.backend) != backendGF256 {
+		return nil, errors.New("access policies are only supported by the default secret sharing backend")
+	}
+
+	secret, flags, err := o.seal(ctx, key)
 	if err != nil {
 		return nil, err
 	}
+	raw, err := shamir.SplitPolicy(secret, policy)
+	if err != nil {
+		return nil, err
+	}
+	for _, shard := range raw {
+		defer Secret(shard.Data).Wipe()
+	}
 	shards = make([]string, len(raw))
 
 	for i, shard := range raw {
-		encoded, err := FromBytes(shard, withOptions...)
+		encoded, err := encodeShard(&envelope{
+			flags:   flags | envelopeGrouped,
+			path:    shard.Path,
+			payload: shard.Data,
+		}, o.writer...)
 		if err != nil {
 			return nil, err
 		}
@@ -101,3 +228,364 @@ func Split(
 
 	return shards, nil
 }
+
+func newSplitOptions(withOptions []SplitOption) (*splitOptions, error) {
+	o := &splitOptions{}
+	for i, option := range withOptions {
+		if err := option.applySplitOption(o); err != nil {
+			o.wipe()
+			return nil, fmt.Errorf("cannot apply option %d to Kids Words split: %w", i+1, err)
+		}
+	}
+	return o, nil
+}
+
+// wipe overwrites the passphrase and the decoy, which are no longer needed once the shards are split.
+func (o *splitOptions) wipe() {
+	o.passphrase.Wipe()
+	if o.decoy != nil {
+		Secret(o.decoy.secret).Wipe()
+		Secret(o.decoy.passphrase).Wipe()
+	}
+}
+
+// seal wraps the key into a time-lock puzzle and encrypts it with a passphrase when the corresponding options are set, returning the envelope flags that mark the shards. The passphrase is checked first during recovery, before the slow work of solving the puzzle. The key is not modified, and it is returned as the secret when neither option is set.
+func (o *splitOptions) seal(ctx context.Context, key []byte) (secret []byte, flags uint16, err error) {
+	secret = key
+	decoy := o.decoy
+	if o.timeLock != nil {
+		if secret, err = o.timeLock.lock(ctx, secret); err != nil {
+			return nil, 0, err
+		}
+		if decoy != nil {
+			// the decoy is locked as well, or it would stand out
+			locked, err := o.timeLock.lock(ctx, decoy.secret)
+			if err != nil {
+				return nil, 0, err
+			}
+			defer Secret(locked).Wipe()
+			decoy = &sealSlot{secret: locked, passphrase: decoy.passphrase}
+		}
+		flags |= envelopeTimeLocked
+	}
+
+	if o.passphrase == nil {
+		if o.argon != nil {
+			return nil, 0, errors.New("Argon parameters require a passphrase")
+		}
+		if o.decoy != nil {
+			return nil, 0, errors.New("decoy secret requires a passphrase")
+		}
+		if o.deniable {
+			return nil, 0, errors.New("deniability requires a passphrase")
+		}
+		return secret, flags, nil
+	}
+	argon := DefaultArgonParameters
+	if o.argon != nil {
+		argon = *o.argon
+	}
+	if secret, err = seal(sealSlot{
+		secret:     secret,
+		passphrase: o.passphrase,
+	}, decoy, o.deniable, argon); err != nil {
+		return nil, 0, err
+	}
+	return secret, flags | envelopeSealed, nil
+}
+
+func encodeShard(e *envelope, withOptions ...WriterOption) (string, error) {
+	b, err := e.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return FromBytes(b, withOptions...)
+}
+
+// ImportShare encodes a share created by [shamir.Split] into a Kid Words shard, which [Combine] accepts alongside the shards created by [Split]. Use it to migrate existing shares, like HashiCorp Vault unseal keys decoded by [shamir.ParseVaultKey].
+func ImportShare(share []byte, withOptions ...WriterOption) (string, error) {
+	if len(share) < 2 {
+		return "", errors.New("share must be at least two bytes")
+	}
+	return encodeShard(&envelope{payload: share}, withOptions...)
+}
+
+// ExportShare reverses [ImportShare] and returns the share in [shamir.Split] format, which can be printed as a HashiCorp Vault unseal key by [shamir.FormatVaultKey].
+func ExportShare(shard string, withOptions ...ReaderOption) ([]byte, error) {
+	b, err := ToBytes(shard, withOptions...)
+	if err != nil {
+		return nil, err
+	}
+	e := &envelope{}
+	if err = e.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	if e.flags&envelopeGrouped != 0 {
+		return nil, errors.New("shards that belong to a group cannot be exported")
+	}
+	if e.backend != backendGF256 {
+		return nil, errors.New("only shards of the default secret sharing backend can be exported")
+	}
+	if e.flags&envelopeSealed != 0 {
+		return nil, errors.New("shards protected by a passphrase cannot be exported")
+	}
+	if e.flags&envelopeTimeLocked != 0 {
+		return nil, errors.New("time-locked shards cannot be exported")
+	}
+	return e.payload, nil
+}
+
+// ShardNumber returns the number of a decoded shard created by [Split] with [WithSequentialShards] option. Compare it with the number printed next to the shard to catch shards that were mixed up or mistyped.
+func ShardNumber(shard []byte) (int, error) {
+	e := &envelope{}
+	if err := e.UnmarshalBinary(shard); err != nil {
+		return 0, err
+	}
+	if e.flags&envelopeGrouped != 0 {
+		return 0, errors.New("shards that belong to a group are not numbered")
+	}
+	if e.flags&envelopeSequential == 0 {
+		return 0, errors.New("shard was not numbered by WithSequentialShards option, so its tag is random")
+	}
+	index, err := shamir.Index(e.payload)
+	if err != nil {
+		return 0, err
+	}
+	return int(index), nil
+}
+
+// Combine recovers the key from a quorum of shards created by [Split] or [SplitPolicy]. Shards printed before their headers recorded the options are recognized, when the first byte of no shard parses as a header, and combined by [shamir.Combine]. Use [WithLegacyShards] to skip the guess.
+func Combine(shards []string, withOptions ...CombineOption) (string, error) {
+	return CombineContext(context.Background(), shards, withOptions...)
+}
+
+// CombineContext works like [Combine], but stops when the context is done, which matters for shards protected by [WithTimeLock] option.
+func CombineContext(ctx context.Context, shards []string, withOptions ...CombineOption) (string, error) {
+	key, err := combineWords(ctx, shards, withOptions)
+	if err != nil {
+		return "", err
+	}
+	defer Secret(key).Wipe()
+	return string(key), nil
+}
+
+// combineWords decodes the shards and recovers the key, which the caller should wipe.
+func combineWords(ctx context.Context, shards []string, withOptions []CombineOption) ([]byte, error) {
+	o, err := newCombineOptions(withOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer o.passphrase.Wipe()
+	raw := make([][]byte, len(shards))
+	erasures := make([][]int, len(shards))
+	for i, shard := range shards {
+		r, err := NewReader(strings.NewReader(shard), o.reader...)
+		if err != nil {
+			return nil, err
+		}
+		if raw[i], err = io.ReadAll(contextReader{ctx: ctx, r: r}); err != nil {
+			return nil, fmt.Errorf("cannot decode shard %d: %w", i+1, err)
+		}
+		defer Secret(raw[i]).Wipe()
+		erasures[i] = r.Erasures()
+	}
+	return combine(ctx, raw, erasures, o)
+}
+
+// wipeAll overwrites every slice with zeros.
+func wipeAll(secrets [][]byte) {
+	for _, secret := range secrets {
+		Secret(secret).Wipe()
+	}
+}
+
+// CombineBytes recovers the key from a quorum of shards that were already decoded from Kid Words.
+func CombineBytes(shards [][]byte, withOptions ...CombineOption) ([]byte, error) {
+	return CombineBytesContext(context.Background(), shards, withOptions...)
+}
+
+// CombineBytesContext works like [CombineBytes], but stops when the context is done.
+func CombineBytesContext(ctx context.Context, shards [][]byte, withOptions ...CombineOption) ([]byte, error) {
+	o, err := newCombineOptions(withOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer o.passphrase.Wipe()
+	return combine(ctx, shards, nil, o)
+}
+
+// CombineErasures recovers the key from shards that were already decoded from Kid Words with [WithErasures] option and the positions of their erased bytes reported by [Reader.Erasures], one list per shard. Each erased byte is recovered from the shards that kept it, which requires more than a quorum of shards torn in different places and the default [shamir.GF256] backend. Every byte must be kept by as many shards as the threshold recorded by [Split]. Shards that do not record it, like those created by [ImportShare], cannot be recovered with erasures.
+func CombineErasures(shards [][]byte, erasures [][]int, withOptions ...CombineOption) ([]byte, error) {
+	return CombineErasuresContext(context.Background(), shards, erasures, withOptions...)
+}
+
+// CombineErasuresContext works like [CombineErasures], but stops when the context is done.
+func CombineErasuresContext(ctx context.Context, shards [][]byte, erasures [][]int, withOptions ...CombineOption) ([]byte, error) {
+	o, err := newCombineOptions(withOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer o.passphrase.Wipe()
+	if len(erasures) != len(shards) {
+		return nil, fmt.Errorf("%d erasure lists provided for %d shards", len(erasures), len(shards))
+	}
+	return combine(ctx, shards, erasures, o)
+}
+
+func newCombineOptions(withOptions []CombineOption) (*combineOptions, error) {
+	o := &combineOptions{}
+	for i, option := range withOptions {
+		if err := option.applyCombineOption(o); err != nil {
+			o.passphrase.Wipe()
+			return nil, fmt.Errorf("cannot apply option %d to Kids Words combine: %w", i+1, err)
+		}
+	}
+	return o, nil
+}
+
+// combine takes erasures as a list of erased byte positions for each shard or nil when nothing is erased.
+func combine(ctx context.Context, shards [][]byte, erasures [][]int, o *combineOptions) ([]byte, error) {
+	if len(shards) == 0 {
+		return nil, errors.New("no shards provided")
+	}
+	if o.legacy {
+		return combineLegacy(shards, erasures, o)
+	}
+	envelopes, payloadErasures, err := readEnvelopes(shards, erasures)
+	if err != nil {
+		if !hasEnvelope(shards) {
+			// shards without headers were printed before envelopes were introduced, a mistyped header of a newer shard must not fall back to the unchecked legacy path
+			if secret, legacyErr := combineLegacy(shards, erasures, o); legacyErr == nil {
+				return secret, nil
+			}
+		}
+		return nil, err
+	}
+
+	secret, err := combineEnvelopes(envelopes, payloadErasures, o)
+	if err != nil {
+		return nil, err
+	}
+	if envelopes[0].flags&envelopeSealed != 0 {
+		if secret, err = unseal(secret, o.passphrase); err != nil {
+			return nil, err
+		}
+	} else if o.passphrase != nil {
+		return nil, errors.New("shards are not protected by a passphrase")
+	}
+	if envelopes[0].flags&envelopeTimeLocked != 0 {
+		return unlock(ctx, secret, o.progress)
+	}
+	return secret, nil
+}
+
+// readEnvelopes parses shard headers, checks that they agree, and moves erased positions into the payloads.
+func readEnvelopes(shards [][]byte, erasures [][]int) (envelopes []envelope, payloadErasures [][]int, err error) {
+	envelopes = make([]envelope, len(shards))
+	for i, shard := range shards {
+		if err := envelopes[i].UnmarshalBinary(shard); err != nil {
+			if erasures != nil && len(erasures[i]) > 0 {
+				return nil, nil, fmt.Errorf("cannot read shard %d with erased words: %w", i+1, err)
+			}
+			return nil, nil, fmt.Errorf("cannot read shard %d: %w", i+1, err)
+		}
+		if erasures != nil && len(erasures[i]) > 0 {
+			if payloadErasures == nil {
+				payloadErasures = make([][]int, len(shards))
+			}
+			header := len(shard) - len(envelopes[i].payload)
+			for _, position := range erasures[i] {
+				if position < header {
+					return nil, nil, fmt.Errorf("shard %d has erased words in its header, which cannot be recovered", i+1)
+				}
+				payloadErasures[i] = append(payloadErasures[i], position-header)
+			}
+		}
+		if envelopes[i].backend != envelopes[0].backend || !bytes.Equal(envelopes[i].digest, envelopes[0].digest) {
+			return nil, nil, fmt.Errorf("shard %d uses a different secret sharing backend", i+1)
+		}
+		if envelopes[i].flags != envelopes[0].flags || envelopes[i].threshold != envelopes[0].threshold {
+			return nil, nil, fmt.Errorf("shard %d disagrees with shard 1 on grouping, threshold, numbering, passphrase protection or time-lock", i+1)
+		}
+	}
+	return envelopes, payloadErasures, nil
+}
+
+// hasEnvelope reports whether the header of any shard parses. When one does, the shards were more likely created with headers and mistyped or mixed up than created without headers, and [shamir.Combine] cannot tell a wrong key from the right one.
+func hasEnvelope(shards [][]byte) bool {
+	for _, shard := range shards {
+		if err := (&envelope{}).UnmarshalBinary(shard); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// combineLegacy recovers the key from headerless shards, which [Split] created before shard envelopes recorded the options, by plain [shamir.Combine].
+func combineLegacy(shards [][]byte, erasures [][]int, o *combineOptions) ([]byte, error) {
+	for i := range erasures {
+		if len(erasures[i]) > 0 {
+			return nil, errors.New("erased words cannot be recovered from shards without headers")
+		}
+	}
+	if o.passphrase != nil {
+		return nil, errors.New("shards without headers are not protected by a passphrase")
+	}
+	if backendID(o.backend) != backendGF256 {
+		return nil, errors.New("shards without headers were created by the default secret sharing backend")
+	}
+	return shamir.Combine(shards)
+}
+
+func combineEnvelopes(envelopes []envelope, erasures [][]int, o *combineOptions) ([]byte, error) {
+	if erasures != nil {
+		if envelopes[0].flags&envelopeGrouped != 0 {
+			return nil, errors.New("erased words cannot be recovered from shards that belong to a group")
+		}
+		if _, err := backendByID(envelopes[0].backend, envelopes[0].digest, o.backend); err != nil {
+			return nil, err
+		}
+		if envelopes[0].backend != backendGF256 {
+			return nil, errors.New("erased words can only be recovered from shards created by the default GF256 backend")
+		}
+		threshold := int(envelopes[0].threshold)
+		if threshold == 0 {
+			// a byte interpolated from too few shards would be silently wrong
+			return nil, errors.New("erased words can only be recovered from shards that record their threshold")
+		}
+		parts := make([][]byte, len(envelopes))
+		for i, e := range envelopes {
+			if e.flags&envelopeGrouped != 0 {
+				return nil, fmt.Errorf("shard %d belongs to a group", i+1)
+			}
+			parts[i] = e.payload
+		}
+		return shamir.CombineErasures(parts, erasures, threshold)
+	}
+	if envelopes[0].flags&envelopeGrouped != 0 {
+		parts := make([]shamir.PolicyShare, len(envelopes))
+		for i, e := range envelopes {
+			if e.flags&envelopeGrouped == 0 {
+				return nil, fmt.Errorf("shard %d does not belong to a group", i+1)
+			}
+			parts[i] = shamir.PolicyShare{Path: e.path, Data: e.payload}
+		}
+		return shamir.CombinePolicy(parts)
+	}
+
+	if threshold := int(envelopes[0].threshold); len(envelopes) < threshold {
+		return nil, fmt.Errorf("%d shards provided, but %d are required to recover the secret", len(envelopes), threshold)
+	}
+	parts := make([][]byte, len(envelopes))
+	for i, e := range envelopes {
+		if e.flags&envelopeGrouped != 0 {
+			return nil, fmt.Errorf("shard %d belongs to a group", i+1)
+		}
+		parts[i] = e.payload
+	}
+	backend, err := backendByID(envelopes[0].backend, envelopes[0].digest, o.backend)
+	if err != nil {
+		return nil, err
+	}
+	return backend.Combine(parts)
+}
@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"compress/gzip"
 	"io"
+	"math/big"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/dkotik/kidwords/dictionary"
+	"github.com/dkotik/kidwords/shamir"
 )
 
 func TestSplit(t *testing.T) {
@@ -23,6 +27,121 @@ func TestSplit(t *testing.T) {
 	// t.Fatal("show")
 }
 
+func TestCombine(t *testing.T) {
+	shards, err := Split("somethingElse", 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := Combine(shards[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+}
+
+func TestCombineLegacyShards(t *testing.T) {
+	// shards printed before envelopes were plain shares encoded into words
+	raw, err := shamir.Split([]byte("somethingElse"), 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for hasEnvelope(raw) { // random first bytes parse as headers one time in eight
+		if raw, err = shamir.Split([]byte("somethingElse"), 5, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shards := make([]string, len(raw))
+	for i, share := range raw {
+		if shards[i], err = FromBytes(share); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	key, err := Combine(shards[:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+	key, err = Combine(shards[2:], WithLegacyShards())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+	if _, err = Combine(shards[:3], WithPassphrase([]byte("correct horse"))); err == nil {
+		t.Fatal("legacy shards were combined with a passphrase")
+	}
+}
+
+func TestCombineCorruptHeader(t *testing.T) {
+	for _, options := range [][]SplitOption{
+		nil,
+		{WithPassphrase([]byte("correct horse")), WithArgonParameters(ArgonParameters{TimeCost: 1, MemoryCost: 64, ParallelThreads: 1})},
+	} {
+		shards, err := Split("somethingElse", 5, 3, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		words := strings.Fields(shards[0])
+		words[0] = dictionary.EnglishFourLetterNouns[0] // envelope version 0
+		shards[0] = strings.Join(words, " ")
+		if key, err := Combine(shards[:3]); err == nil {
+			t.Fatalf("shards with a corrupt header were combined into %q", key)
+		}
+	}
+}
+
+func TestSplitBytes(t *testing.T) {
+	key := []byte("somethingElse")
+	shards, err := SplitBytes(key, 5, 3, WithPassphrase([]byte("correct horse")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(key) != "somethingElse" {
+		t.Fatalf("key was modified into %q", key)
+	}
+	recovered, err := Combine(shards[1:4], WithPassphrase([]byte("correct horse")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", recovered)
+	}
+}
+
+func TestSplitPolicy(t *testing.T) {
+	shards, err := SplitPolicy("somethingElse", shamir.All(
+		shamir.Any(shamir.Participants(2)...),
+		shamir.Threshold(2, shamir.Participants(3)...),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shards) != 5 {
+		t.Fatalf("expected 5 shards, got %d", len(shards))
+	}
+
+	key, err := Combine([]string{shards[1], shards[2], shards[4]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+
+	if _, err = Combine(shards[:3]); err == nil {
+		t.Fatal("shards that do not satisfy the policy recovered the key")
+	}
+	if _, err = Combine([]string{shards[0], shards[1]}); err == nil {
+		t.Fatal("shards from one group recovered the key")
+	}
+}
+
 func compress(r io.Reader) ([]byte, error) {
 	b := &bytes.Buffer{}
 	zr, err := gzip.NewWriterLevel(b, gzip.BestCompression)
@@ -77,3 +196,371 @@ func TestProveCompressionIsPointless(t *testing.T) {
 	}
 	// t.Fatal("compressed poorly")
 }
+
+func TestShardNumber(t *testing.T) {
+	shards, err := Split("somethingElse", 5, 3, WithSequentialShards())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, shard := range shards {
+		b, err := ToBytes(shard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := ShardNumber(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != i+1 {
+			t.Fatalf("shard number %d does not match its position %d", n, i+1)
+		}
+	}
+
+	if shards, err = Split("somethingElse", 5, 3); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ToBytes(shards[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := ShardNumber(b); err == nil {
+		t.Fatalf("random shard tag was reported as shard number %d", n)
+	}
+}
+
+func TestSplitWithBackend(t *testing.T) {
+	f, err := shamir.NewPrimeField(shamir.Mersenne61)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shards, err := Split("somethingElse", 5, 3, WithBackend(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := Combine(shards[:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+
+	custom, err := shamir.NewPrimeField(big.NewInt(4294967291))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shards, err = Split("somethingElse", 5, 3, WithBackend(custom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Combine(shards[:3]); err == nil {
+		t.Fatal("custom backend must be provided to combine")
+	}
+	other, err := shamir.NewPrimeField(big.NewInt(4294967279))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, err = Combine(shards[:3], WithBackend(other)); err == nil {
+		t.Fatalf("different custom prime was accepted and combined into %q", key)
+	}
+	key, err = Combine(shards[:3], WithBackend(custom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+}
+
+func TestImportShare(t *testing.T) {
+	parts, err := shamir.Split([]byte("somethingElse"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shards := make([]string, len(parts))
+	for i, part := range parts {
+		key := shamir.FormatVaultKey(part)
+		share, err := shamir.ParseVaultKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shards[i], err = ImportShare(share); err != nil {
+			t.Fatal(err)
+		}
+		exported, err := ExportShare(shards[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shamir.FormatVaultKey(exported) != key {
+			t.Fatalf("exported share %d does not match the unseal key", i+1)
+		}
+	}
+
+	key, err := Combine(shards[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+}
+
+func TestImportSLIP39(t *testing.T) {
+	mnemonic := "duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard"
+	shard, err := ImportSLIP39(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := ExportSLIP39(shard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != mnemonic {
+		t.Fatalf("exported mnemonic %q does not match", exported)
+	}
+	if _, err = Combine([]string{shard}); err == nil {
+		t.Fatal("SLIP-39 shard was combined as a Kid Words shard")
+	}
+	if _, err = ExportShare(shard); err == nil {
+		t.Fatal("SLIP-39 shard was exported as a Shamir share")
+	}
+}
+
+func TestSplitWithPassphrase(t *testing.T) {
+	cheap := WithArgonParameters(ArgonParameters{
+		TimeCost:        1,
+		MemoryCost:      64,
+		ParallelThreads: 1,
+	})
+	passphrase := []byte("open sesame")
+	shards, err := Split("somethingElse", 5, 3, WithPassphrase(passphrase), cheap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(passphrase, make([]byte, len(passphrase))) {
+		t.Fatal("passphrase was not wiped after split")
+	}
+	passphrase = []byte("open sesame")
+	key, err := Combine(shards[2:], WithPassphrase(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(passphrase, make([]byte, len(passphrase))) {
+		t.Fatal("passphrase was not wiped after combine")
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+	if _, err = Combine(shards[2:], WithPassphrase([]byte("open barley"))); err == nil {
+		t.Fatal("wrong passphrase recovered the key")
+	}
+	if _, err = Combine(shards[2:]); err == nil {
+		t.Fatal("missing passphrase recovered the key")
+	}
+	if _, err = ExportShare(shards[0]); err == nil {
+		t.Fatal("protected shard was exported")
+	}
+
+	// a short key must stay short enough to copy by hand: the version, costs, salt, padding and tag
+	unsealed, err := Split("7 bytes", 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := Split("7 bytes", 5, 3, WithPassphrase([]byte("open sesame")), cheap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overhead := len(strings.Fields(sealed[0])) - len(strings.Fields(unsealed[0])); overhead > 5+sealSaltSize+sealBlockSize+sealTagSize {
+		t.Fatalf("passphrase added %d words to each shard", overhead)
+	}
+
+	policy := shamir.Any(shamir.Participant(), shamir.Threshold(2, shamir.Participants(3)...))
+	if shards, err = SplitPolicy("somethingElse", policy, WithPassphrase([]byte("open sesame")), cheap); err != nil {
+		t.Fatal(err)
+	}
+	if key, err = Combine(shards[:1], WithPassphrase([]byte("open sesame"))); err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+
+	if _, err = Split("somethingElse", 5, 3, cheap); err == nil {
+		t.Fatal("Argon parameters were accepted without a passphrase")
+	}
+	if shards, err = Split("somethingElse", 5, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Combine(shards, WithPassphrase([]byte("open sesame"))); err == nil {
+		t.Fatal("passphrase was accepted for unprotected shards")
+	}
+}
+
+func TestSplitWithDecoy(t *testing.T) {
+	cheap := WithArgonParameters(ArgonParameters{
+		TimeCost:        1,
+		MemoryCost:      64,
+		ParallelThreads: 1,
+	})
+	shards, err := Split("somethingElse", 5, 3,
+		WithPassphrase([]byte("open sesame")),
+		WithDecoy([]byte("open barley"), []byte("harmless")),
+		cheap,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for passphrase, expected := range map[string]string{
+		"open sesame": "somethingElse",
+		"open barley": "harmless",
+	} {
+		key, err := Combine(shards[:3], WithPassphrase([]byte(passphrase)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != expected {
+			t.Fatalf("passphrase %q recovered %q instead of %q", passphrase, key, expected)
+		}
+	}
+	if _, err = Combine(shards[:3], WithPassphrase([]byte("open wheat"))); err == nil {
+		t.Fatal("wrong passphrase recovered a secret")
+	}
+
+	// whoever is given the decoy passphrase sees the decoy and the length of the shards, which must not hint at another secret
+	plain, err := Split("somethingElse", 5, 3, WithPassphrase([]byte("open sesame")), WithDeniability(), cheap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, decoy := range []string{"h", "harmless", "a decoy that is longer than the secret", strings.Repeat("d", sealSlotSize-1)} {
+		withDecoy, err := Split("somethingElse", 5, 3, WithPassphrase([]byte("open sesame")), WithDecoy([]byte("open barley"), []byte(decoy)), cheap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoyAlone, err := Split(decoy, 5, 3, WithPassphrase([]byte("open barley")), WithDeniability(), cheap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(withDecoy[0]) != len(plain[0]) || len(withDecoy[0]) != len(decoyAlone[0]) {
+			t.Fatalf("shards with a decoy of %d bytes reveal the decoy by their length", len(decoy))
+		}
+	}
+	if _, err = Split("somethingElse", 5, 3, WithPassphrase([]byte("open sesame")), WithDecoy([]byte("open barley"), []byte(strings.Repeat("d", sealSlotSize))), cheap); err == nil {
+		t.Fatal("decoy that pads to a different size was accepted")
+	}
+
+	if _, err = Split("somethingElse", 5, 3, WithDecoy([]byte("open barley"), []byte("harmless"))); err == nil {
+		t.Fatal("decoy was accepted without a passphrase")
+	}
+	if _, err = Split("somethingElse", 5, 3, WithDeniability()); err == nil {
+		t.Fatal("deniability was accepted without a passphrase")
+	}
+	if _, err = Split("somethingElse", 5, 3, WithPassphrase([]byte("open sesame")), WithDecoy([]byte("open sesame"), []byte("harmless")), cheap); err == nil {
+		t.Fatal("decoy was accepted with the same passphrase")
+	}
+}
+
+func TestCombineErasures(t *testing.T) {
+	key := "torn paper key"
+	shards, err := Split(key, 5, 3, WithPassphrase([]byte("faded")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	erase := func(shard string, positions ...int) string {
+		words := strings.Fields(shard)
+		for _, position := range positions {
+			words[position] = "____"
+		}
+		return strings.Join(words, " ")
+	}
+	// the first three words are the envelope header, extended flags and threshold, and the last is the shard tag
+	torn := []string{
+		erase(shards[0], 3, 4, 5),
+		erase(shards[1], 6, 7),
+		erase(shards[2], 8, 9, 10),
+		erase(shards[3], 11, 12),
+	}
+
+	if _, err = Combine(torn, WithPassphrase([]byte("faded"))); err == nil {
+		t.Fatal("placeholders were accepted without erasures option")
+	}
+	recovered, err := Combine(torn, WithPassphrase([]byte("faded")), WithErasures())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != key {
+		t.Fatalf("recovered %q instead of %q", recovered, key)
+	}
+
+	if _, err = Combine(torn[:2], WithPassphrase([]byte("faded")), WithErasures()); err == nil {
+		t.Fatal("key was recovered from too few shards")
+	}
+	for _, position := range []int{0, 1, 2} {
+		if _, err = Combine([]string{erase(shards[0], position), shards[1], shards[2]}, WithPassphrase([]byte("faded")), WithErasures()); err == nil {
+			t.Fatalf("erased header word %d was accepted", position)
+		}
+	}
+
+	plain, err := Split(key, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the fourth word survives in only two shards, which interpolate into a wrong byte
+	if _, err = Combine([]string{erase(plain[0], 3), erase(plain[1], 3), plain[2], plain[3]}, WithErasures()); err == nil {
+		t.Fatal("byte kept by fewer shards than the threshold was recovered")
+	}
+	recovered, err = Combine([]string{erase(plain[0], 3), plain[1], plain[2], plain[3]}, WithErasures())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != key {
+		t.Fatalf("recovered %q instead of %q", recovered, key)
+	}
+
+	imported := make([]string, 4)
+	for i, shard := range plain[:4] {
+		b, err := ExportShare(shard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if imported[i], err = ImportShare(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = Combine(imported[:3], WithErasures()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Combine([]string{erase(imported[0], 1), imported[1], imported[2], imported[3]}, WithErasures()); err == nil {
+		t.Fatal("erasures were accepted for shards that do not record their threshold")
+	}
+
+	// a passphrase does not make up for a missing threshold
+	unrecorded := make([]string, 4)
+	for i, shard := range shards[:4] {
+		b, err := ToBytes(shard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := &envelope{}
+		if err = e.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		e.flags &^= envelopeThreshold
+		if unrecorded[i], err = encodeShard(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = Combine(unrecorded[:3], WithPassphrase([]byte("faded"))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Combine([]string{erase(unrecorded[0], 3), unrecorded[1], unrecorded[2], unrecorded[3]}, WithPassphrase([]byte("faded")), WithErasures()); err == nil {
+		t.Fatal("erasures were accepted for sealed shards that do not record their threshold")
+	}
+
+	grouped, err := SplitPolicy(key, shamir.Threshold(2, shamir.Participants(3)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Combine([]string{erase(grouped[0], len(strings.Fields(grouped[0]))-2), grouped[1], grouped[2]}, WithErasures()); err == nil {
+		t.Fatal("erasures were accepted for grouped shards")
+	}
+}
@@ -0,0 +1,44 @@
+package kidwords
+
+import (
+	"errors"
+
+	"github.com/dkotik/kidwords/slip39"
+)
+
+// ImportSLIP39 encodes a SLIP-0039 mnemonic share into a Kid Words shard, so that hardware wallet backups can be written down with friendlier words. The shard keeps the mnemonic checksum and can only be turned back into the mnemonic by [ExportSLIP39].
+func ImportSLIP39(mnemonic string, withOptions ...WriterOption) (string, error) {
+	share, err := slip39.ParseMnemonic(mnemonic)
+	if err != nil {
+		return "", err
+	}
+	payload, err := share.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return encodeShard(&envelope{
+		flags:   envelopeBackend,
+		backend: backendSLIP39,
+		payload: payload,
+	}, withOptions...)
+}
+
+// ExportSLIP39 reverses [ImportSLIP39] and returns the SLIP-0039 mnemonic share, which can be restored on a hardware wallet or combined by [slip39.Combine].
+func ExportSLIP39(shard string, withOptions ...ReaderOption) (string, error) {
+	b, err := ToBytes(shard, withOptions...)
+	if err != nil {
+		return "", err
+	}
+	e := &envelope{}
+	if err = e.UnmarshalBinary(b); err != nil {
+		return "", err
+	}
+	if e.flags&envelopeGrouped != 0 || e.backend != backendSLIP39 {
+		return "", errors.New("shard does not hold a SLIP-39 share")
+	}
+	share := &slip39.Share{}
+	if err = share.UnmarshalBinary(e.payload); err != nil {
+		return "", err
+	}
+	return share.Mnemonic()
+}
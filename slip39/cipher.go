@@ -0,0 +1,82 @@
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// The master secret is encrypted by a four round Feistel network, which
+// keeps the length of the secret. The round function is PBKDF2-HMAC-SHA256
+// with the round number and passphrase as the password. Every passphrase
+// decrypts to a valid secret, which gives the owner plausible deniability.
+const (
+	baseIterationCount = 10000
+	roundCount         = 4
+)
+
+func cipherSalt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return binary.BigEndian.AppendUint16([]byte(customization), identifier)
+}
+
+func roundFunction(i int, passphrase []byte, exponent uint8, salt, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	return pbkdf2(password, append(append([]byte{}, salt...), r...), (baseIterationCount<<exponent)/roundCount, len(r))
+}
+
+func feistel(secret, passphrase []byte, identifier uint16, exponent uint8, extendable bool, rounds []int) []byte {
+	var (
+		half = len(secret) / 2
+		l    = append([]byte{}, secret[:half]...)
+		r    = append([]byte{}, secret[half:]...)
+		salt = cipherSalt(identifier, extendable)
+	)
+	for _, i := range rounds {
+		f := roundFunction(i, passphrase, exponent, salt, r)
+		for j := range l {
+			l[j] ^= f[j]
+		}
+		l, r = r, l
+	}
+	return append(r, l...)
+}
+
+func encrypt(secret, passphrase []byte, identifier uint16, exponent uint8, extendable bool) []byte {
+	return feistel(secret, passphrase, identifier, exponent, extendable, []int{0, 1, 2, 3})
+}
+
+func decrypt(encrypted, passphrase []byte, identifier uint16, exponent uint8, extendable bool) []byte {
+	return feistel(encrypted, passphrase, identifier, exponent, extendable, []int{3, 2, 1, 0})
+}
+
+// pbkdf2 implements RFC 8018 key derivation with HMAC-SHA256.
+func pbkdf2(password, salt []byte, iterations, keyLength int) []byte {
+	var (
+		prf    = hmac.New(sha256.New, password)
+		out    = make([]byte, 0, keyLength)
+		u      []byte
+		t      = make([]byte, prf.Size())
+		buffer [4]byte
+	)
+	for block := uint32(1); len(out) < keyLength; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buffer[:], block)
+		prf.Write(buffer[:])
+		u = prf.Sum(u[:0])
+		copy(t, u)
+		for n := 1; n < iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		out = append(out, t...)
+	}
+	return out[:keyLength]
+}
@@ -0,0 +1,68 @@
+package slip39
+
+// Mnemonics end with three words of Reed-Solomon checksum over GF(1024),
+// which detects any error that affects at most three words and makes an
+// undetected error with more words less likely than one in a billion. The
+// customization string separates the checksums of the two share formats.
+const (
+	checksumWords          = 3
+	customization          = "shamir"
+	customizationExtended  = "shamir_extendable"
+	checksumSatisfiedValue = 1
+)
+
+var rs1024Generator = [10]uint32{
+	0xe0e040,
+	0x1c1c080,
+	0x3838100,
+	0x7070200,
+	0xe0e0009,
+	0x1c0c2412,
+	0x38086c24,
+	0x3090fc48,
+	0x21b1f890,
+	0x3f3f120,
+}
+
+func rs1024Polymod(values []uint16) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ uint32(v)
+		for i := range rs1024Generator {
+			if b>>i&1 != 0 {
+				chk ^= rs1024Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func checksumPrefix(extendable bool) []uint16 {
+	s := customization
+	if extendable {
+		s = customizationExtended
+	}
+	values := make([]uint16, len(s))
+	for i := range s {
+		values[i] = uint16(s[i])
+	}
+	return values
+}
+
+// rs1024Checksum returns the checksum words of the data words.
+func rs1024Checksum(data []uint16, extendable bool) []uint16 {
+	values := append(checksumPrefix(extendable), data...)
+	values = append(values, make([]uint16, checksumWords)...)
+	polymod := rs1024Polymod(values) ^ checksumSatisfiedValue
+	checksum := make([]uint16, checksumWords)
+	for i := range checksum {
+		checksum[i] = uint16(polymod>>(10*(checksumWords-1-i))) & 1023
+	}
+	return checksum
+}
+
+// rs1024Verify checks the data words that end with their checksum.
+func rs1024Verify(data []uint16, extendable bool) bool {
+	return rs1024Polymod(append(checksumPrefix(extendable), data...)) == checksumSatisfiedValue
+}
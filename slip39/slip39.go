@@ -0,0 +1,389 @@
+/*
+Package slip39 implements SLIP-0039 Shamir's Secret-Sharing for Mnemonic Codes, which hardware wallets use to back up their master secrets as groups of mnemonic shares. Shares can be moved into Kid Words shards with [github.com/dkotik/kidwords.ImportSLIP39] and back.
+
+See https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+*/
+package slip39
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dkotik/kidwords/shamir"
+)
+
+const (
+	radixBits       = 10
+	idLengthBits    = 15
+	maxShareCount   = 16
+	minSecretLength = 16
+	// metadataWords counts the words of the share header and checksum.
+	metadataWords = 7
+	// minMnemonicWords fits the header, checksum, and a minimal secret.
+	minMnemonicWords = metadataWords + (minSecretLength*8+radixBits-1)/radixBits
+)
+
+var wordIndex = func() map[string]uint16 {
+	m := make(map[string]uint16, len(WordList))
+	for i, word := range WordList {
+		m[word] = uint16(i)
+	}
+	return m
+}()
+
+// Group describes how the share of one group is split among its members.
+type Group struct {
+	// Threshold is the number of member shares required to recover the group share.
+	Threshold int
+	// Count is the number of member shares.
+	Count int
+}
+
+// Share is one mnemonic share of a master secret.
+type Share struct {
+	// Identifier is a random 15 bit value common to all shares of the same secret.
+	Identifier uint16
+	// Extendable shares use the identifier only for grouping, so that more share sets of the same secret can be created later.
+	Extendable bool
+	// IterationExponent raises the cost of passphrase encryption to 10000 × 2^e PBKDF2 iterations.
+	IterationExponent uint8
+	// GroupIndex is the index of the group the share belongs to.
+	GroupIndex uint8
+	// GroupThreshold is the number of groups required to recover the master secret.
+	GroupThreshold uint8
+	// GroupCount is the total number of groups.
+	GroupCount uint8
+	// MemberIndex is the index of the share within its group.
+	MemberIndex uint8
+	// MemberThreshold is the number of member shares required to recover the group share.
+	MemberThreshold uint8
+	// Value is the share of the encrypted master secret.
+	Value []byte
+}
+
+func (s *Share) validate() error {
+	if s.Identifier >= 1<<idLengthBits {
+		return fmt.Errorf("share identifier %d is out of range", s.Identifier)
+	}
+	if s.IterationExponent > 15 {
+		return fmt.Errorf("iteration exponent %d is out of range [0-15]", s.IterationExponent)
+	}
+	if s.GroupCount < 1 || s.GroupCount > maxShareCount {
+		return fmt.Errorf("group count %d is out of range [1-%d]", s.GroupCount, maxShareCount)
+	}
+	if s.GroupThreshold < 1 || s.GroupThreshold > s.GroupCount {
+		return fmt.Errorf("group threshold %d is out of range [1-%d]", s.GroupThreshold, s.GroupCount)
+	}
+	if s.GroupIndex >= s.GroupCount {
+		return fmt.Errorf("group index %d is out of range", s.GroupIndex)
+	}
+	if s.MemberThreshold < 1 || s.MemberThreshold > maxShareCount {
+		return fmt.Errorf("member threshold %d is out of range [1-%d]", s.MemberThreshold, maxShareCount)
+	}
+	if s.MemberIndex >= maxShareCount {
+		return fmt.Errorf("member index %d is out of range", s.MemberIndex)
+	}
+	if len(s.Value) < minSecretLength || len(s.Value)%2 != 0 {
+		return fmt.Errorf("share value must be an even number of bytes, at least %d", minSecretLength)
+	}
+	return nil
+}
+
+// words encodes the share into mnemonic word indexes, ending with the checksum.
+func (s *Share) words() ([]uint16, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	var ext uint64
+	if s.Extendable {
+		ext = 1
+	}
+	header := uint64(s.Identifier)<<25 | ext<<24 | uint64(s.IterationExponent)<<20 |
+		uint64(s.GroupIndex)<<16 | uint64(s.GroupThreshold-1)<<12 | uint64(s.GroupCount-1)<<8 |
+		uint64(s.MemberIndex)<<4 | uint64(s.MemberThreshold-1)
+	words := make([]uint16, 0, 4+(len(s.Value)*8+radixBits-1)/radixBits+checksumWords)
+	for i := 3; i >= 0; i-- {
+		words = append(words, uint16(header>>(i*radixBits))&1023)
+	}
+	words = append(words, packWords(s.Value)...)
+	return append(words, rs1024Checksum(words, s.Extendable)...), nil
+}
+
+// fromWords decodes the share from mnemonic word indexes and verifies the checksum.
+func (s *Share) fromWords(words []uint16) error {
+	if len(words) < minMnemonicWords {
+		return fmt.Errorf("mnemonic must be at least %d words long", minMnemonicWords)
+	}
+	extendable := words[1]>>4&1 == 1
+	if !rs1024Verify(words, extendable) {
+		return errors.New("invalid mnemonic checksum")
+	}
+
+	var header uint64
+	for _, word := range words[:4] {
+		header = header<<radixBits | uint64(word)
+	}
+	value, err := unpackWords(words[4 : len(words)-checksumWords])
+	if err != nil {
+		return err
+	}
+	*s = Share{
+		Identifier:        uint16(header >> 25),
+		Extendable:        extendable,
+		IterationExponent: uint8(header >> 20 & 15),
+		GroupIndex:        uint8(header >> 16 & 15),
+		GroupThreshold:    uint8(header>>12&15) + 1,
+		GroupCount:        uint8(header>>8&15) + 1,
+		MemberIndex:       uint8(header >> 4 & 15),
+		MemberThreshold:   uint8(header&15) + 1,
+		Value:             value,
+	}
+	if s.GroupThreshold > s.GroupCount {
+		return errors.New("group threshold cannot exceed group count")
+	}
+	return nil
+}
+
+// packWords converts bytes into 10 bit words padded with zero bits on the left.
+func packWords(b []byte) []uint16 {
+	bits := len(b) * 8
+	count := (bits + radixBits - 1) / radixBits
+	padding := count*radixBits - bits
+	words := make([]uint16, count)
+	for i := 0; i < bits; i++ {
+		if b[i/8]>>(7-i%8)&1 == 1 {
+			position := padding + i
+			words[position/radixBits] |= 1 << (radixBits - 1 - position%radixBits)
+		}
+	}
+	return words
+}
+
+// unpackWords reverses [packWords].
+func unpackWords(words []uint16) ([]byte, error) {
+	bits := len(words) * radixBits
+	padding := bits % 16
+	if padding > 8 {
+		return nil, errors.New("invalid mnemonic length")
+	}
+	if words[0]>>(radixBits-padding) != 0 {
+		return nil, errors.New("invalid mnemonic padding")
+	}
+	b := make([]byte, (bits-padding)/8)
+	for i := range b {
+		for j := 0; j < 8; j++ {
+			position := padding + i*8 + j
+			bit := words[position/radixBits] >> (radixBits - 1 - position%radixBits) & 1
+			b[i] |= byte(bit) << (7 - j)
+		}
+	}
+	return b, nil
+}
+
+// Mnemonic returns the share as words of [WordList].
+func (s *Share) Mnemonic() (string, error) {
+	words, err := s.words()
+	if err != nil {
+		return "", err
+	}
+	b := &strings.Builder{}
+	for i, word := range words {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(WordList[word])
+	}
+	return b.String(), nil
+}
+
+// ParseMnemonic decodes a mnemonic share and verifies its checksum. Words are case insensitive.
+func ParseMnemonic(mnemonic string) (*Share, error) {
+	fields := strings.Fields(strings.ToLower(mnemonic))
+	words := make([]uint16, len(fields))
+	for i, field := range fields {
+		index, ok := wordIndex[field]
+		if !ok {
+			return nil, fmt.Errorf("word %d %q is not in SLIP-0039 word list", i+1, field)
+		}
+		words[i] = index
+	}
+	s := &Share{}
+	if err := s.fromWords(words); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MarshalBinary packs the mnemonic words, including the checksum, into bytes ten bits per word. The encoding is compact enough to be written down as Kid Words.
+func (s *Share) MarshalBinary() ([]byte, error) {
+	words, err := s.words()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, (len(words)*radixBits+7)/8)
+	for i, word := range words {
+		for j := 0; j < radixBits; j++ {
+			if word>>(radixBits-1-j)&1 == 1 {
+				position := i*radixBits + j
+				b[position/8] |= 1 << (7 - position%8)
+			}
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary reverses [Share.MarshalBinary] and verifies the checksum.
+func (s *Share) UnmarshalBinary(b []byte) error {
+	count := len(b) * 8 / radixBits
+	words := make([]uint16, count)
+	for position := 0; position < len(b)*8; position++ {
+		bit := b[position/8] >> (7 - position%8) & 1
+		if position >= count*radixBits {
+			if bit != 0 {
+				return errors.New("invalid share padding")
+			}
+			continue
+		}
+		words[position/radixBits] |= uint16(bit) << (radixBits - 1 - position%radixBits)
+	}
+	return s.fromWords(words)
+}
+
+// Split encrypts the master secret with the passphrase and splits it into groups of shares, any groupThreshold of which recover the secret when each meets its [Group.Threshold]. The master secret must be an even number of bytes, at least 16. Each increment of the iteration exponent doubles the time it takes to decrypt the secret. The returned shares are extendable.
+func Split(secret, passphrase []byte, iterationExponent uint8, groupThreshold int, groups ...Group) ([][]Share, error) {
+	if len(secret) < minSecretLength || len(secret)%2 != 0 {
+		return nil, fmt.Errorf("master secret must be an even number of bytes, at least %d", minSecretLength)
+	}
+	if iterationExponent > 15 {
+		return nil, fmt.Errorf("iteration exponent %d is out of range [0-15]", iterationExponent)
+	}
+	if len(groups) < 1 || len(groups) > maxShareCount {
+		return nil, fmt.Errorf("group count %d is out of range [1-%d]", len(groups), maxShareCount)
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) {
+		return nil, fmt.Errorf("group threshold %d is out of range [1-%d]", groupThreshold, len(groups))
+	}
+	for i, group := range groups {
+		if group.Count < 1 || group.Count > maxShareCount {
+			return nil, fmt.Errorf("group %d: member count %d is out of range [1-%d]", i+1, group.Count, maxShareCount)
+		}
+		if group.Threshold < 1 || group.Threshold > group.Count {
+			return nil, fmt.Errorf("group %d: member threshold %d is out of range [1-%d]", i+1, group.Threshold, group.Count)
+		}
+		if group.Threshold == 1 && group.Count > 1 {
+			return nil, fmt.Errorf("group %d: use a single member share instead of many with threshold 1", i+1)
+		}
+	}
+
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	identifier := binary.BigEndian.Uint16(id[:]) >> (16 - idLengthBits)
+	encrypted := encrypt(secret, passphrase, identifier, iterationExponent, true)
+
+	groupShares, err := shamir.SplitSLIP39(encrypted, len(groups), groupThreshold)
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]Share, len(groups))
+	for i, group := range groups {
+		memberShares, err := shamir.SplitSLIP39(groupShares[i][:len(secret)], group.Count, group.Threshold)
+		if err != nil {
+			return nil, fmt.Errorf("group %d: %w", i+1, err)
+		}
+		result[i] = make([]Share, group.Count)
+		for j, value := range memberShares {
+			result[i][j] = Share{
+				Identifier:        identifier,
+				Extendable:        true,
+				IterationExponent: iterationExponent,
+				GroupIndex:        uint8(i),
+				GroupThreshold:    uint8(groupThreshold),
+				GroupCount:        uint8(len(groups)),
+				MemberIndex:       uint8(j),
+				MemberThreshold:   uint8(group.Threshold),
+				Value:             value[:len(secret)],
+			}
+		}
+	}
+	return result, nil
+}
+
+// Combine decodes mnemonic shares and recovers the master secret using [CombineShares].
+func Combine(mnemonics []string, passphrase []byte) ([]byte, error) {
+	shares := make([]Share, len(mnemonics))
+	for i, mnemonic := range mnemonics {
+		share, err := ParseMnemonic(mnemonic)
+		if err != nil {
+			return nil, fmt.Errorf("mnemonic %d: %w", i+1, err)
+		}
+		shares[i] = *share
+	}
+	return CombineShares(shares, passphrase)
+}
+
+// CombineShares recovers the master secret from shares that satisfy the group threshold and decrypts it with the passphrase. A wrong passphrase produces a different secret rather than an error, as required by the standard. Groups that lack their member threshold are skipped.
+func CombineShares(shares []Share, passphrase []byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares provided")
+	}
+	first := shares[0]
+	var (
+		order   []uint8
+		members = make(map[uint8][]Share)
+	)
+	for i, share := range shares {
+		if err := share.validate(); err != nil {
+			return nil, fmt.Errorf("share %d: %w", i+1, err)
+		}
+		if share.Identifier != first.Identifier || share.Extendable != first.Extendable || share.IterationExponent != first.IterationExponent {
+			return nil, fmt.Errorf("share %d belongs to a different secret", i+1)
+		}
+		if share.GroupThreshold != first.GroupThreshold || share.GroupCount != first.GroupCount {
+			return nil, fmt.Errorf("share %d disagrees on group parameters", i+1)
+		}
+		if len(share.Value) != len(first.Value) {
+			return nil, fmt.Errorf("share %d has a different length", i+1)
+		}
+		group, ok := members[share.GroupIndex]
+		if !ok {
+			order = append(order, share.GroupIndex)
+		} else if group[0].MemberThreshold != share.MemberThreshold {
+			return nil, fmt.Errorf("share %d disagrees on member threshold of group %d", i+1, share.GroupIndex+1)
+		}
+		members[share.GroupIndex] = append(group, share)
+	}
+
+	var (
+		groupShares [][]byte
+		lastErr     error
+	)
+	for _, index := range order {
+		group := members[index]
+		parts := make([][]byte, len(group))
+		for i, share := range group {
+			parts[i] = append(append([]byte{}, share.Value...), share.MemberIndex)
+		}
+		groupSecret, err := shamir.CombineSLIP39(parts, int(group[0].MemberThreshold))
+		if err != nil {
+			lastErr = fmt.Errorf("group %d: %w", index+1, err)
+			continue
+		}
+		groupShares = append(groupShares, append(groupSecret, index))
+	}
+	if len(groupShares) < int(first.GroupThreshold) {
+		if lastErr != nil {
+			return nil, fmt.Errorf("recovered %d of %d required groups: %w", len(groupShares), first.GroupThreshold, lastErr)
+		}
+		return nil, fmt.Errorf("recovered %d of %d required groups", len(groupShares), first.GroupThreshold)
+	}
+	encrypted, err := shamir.CombineSLIP39(groupShares, int(first.GroupThreshold))
+	if err != nil {
+		return nil, err
+	}
+	return decrypt(encrypted, passphrase, first.Identifier, first.IterationExponent, first.Extendable), nil
+}
@@ -0,0 +1,185 @@
+package slip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Vectors from the SLIP-0039 specification, all encrypted with "TREZOR" passphrase.
+var testVectors = []struct {
+	Name      string
+	Mnemonics []string
+	Secret    string
+}{
+	{
+		Name: "one share",
+		Mnemonics: []string{
+			"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard",
+		},
+		Secret: "bb54aac4b89dc868ba37d9cc21b2cece",
+	},
+	{
+		Name: "two of three shares",
+		Mnemonics: []string{
+			"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed",
+			"shadow pistol academic acid actress prayer class unknown daughter sweater depict flip twice unkind craft early superior advocate guest smoking",
+		},
+		Secret: "b43ceb7e57a0ea8766221624d01b0864",
+	},
+}
+
+func TestWordList(t *testing.T) {
+	prefixes := make(map[string]bool)
+	for i, word := range WordList {
+		if len(word) < 4 || len(word) > 8 {
+			t.Fatalf("word %q has unexpected length", word)
+		}
+		if i > 0 && WordList[i-1] >= word {
+			t.Fatalf("word %q is out of order", word)
+		}
+		if prefixes[word[:4]] {
+			t.Fatalf("word %q prefix is not unique", word)
+		}
+		prefixes[word[:4]] = true
+	}
+}
+
+func TestCombineVectors(t *testing.T) {
+	for _, vector := range testVectors {
+		t.Run(vector.Name, func(t *testing.T) {
+			secret, err := Combine(vector.Mnemonics, []byte("TREZOR"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(secret) != vector.Secret {
+				t.Fatalf("recovered %x instead of %s", secret, vector.Secret)
+			}
+
+			for _, mnemonic := range vector.Mnemonics {
+				share, err := ParseMnemonic(mnemonic)
+				if err != nil {
+					t.Fatal(err)
+				}
+				encoded, err := share.Mnemonic()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if encoded != mnemonic {
+					t.Fatalf("mnemonic %q was encoded as %q", mnemonic, encoded)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMnemonicErrors(t *testing.T) {
+	for mnemonic, reason := range map[string]string{
+		"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision kidney":  "checksum",
+		"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision":         "length",
+		"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keybord": "unknown word",
+	} {
+		if _, err := ParseMnemonic(mnemonic); err == nil {
+			t.Fatalf("mnemonic with invalid %s was accepted", reason)
+		}
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("sixteen byte key and then some..")
+	passphrase := []byte("correct horse")
+	groups, err := Split(secret, passphrase, 0, 2,
+		Group{Threshold: 1, Count: 1},
+		Group{Threshold: 2, Count: 3},
+		Group{Threshold: 3, Count: 5},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Name   string
+		Shares []Share
+		Fails  bool
+	}{
+		{Name: "first and second groups", Shares: []Share{groups[0][0], groups[1][2], groups[1][0]}},
+		{Name: "second and third groups", Shares: []Share{groups[2][4], groups[1][1], groups[2][0], groups[1][2], groups[2][1]}},
+		{Name: "incomplete third group", Shares: []Share{groups[0][0], groups[2][0], groups[2][1]}, Fails: true},
+		{Name: "single group", Shares: []Share{groups[1][0], groups[1][1], groups[1][2]}, Fails: true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			mnemonics := make([]string, len(c.Shares))
+			for i, share := range c.Shares {
+				if mnemonics[i], err = share.Mnemonic(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			recovered, err := Combine(mnemonics, passphrase)
+			if c.Fails {
+				if err == nil {
+					t.Fatal("shares that do not satisfy the group threshold recovered a secret")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(recovered, secret) {
+				t.Fatalf("recovered %q instead of %q", recovered, secret)
+			}
+		})
+	}
+
+	wrong, err := CombineShares([]Share{groups[0][0], groups[1][0], groups[1][1]}, []byte("wrong passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(wrong, secret) {
+		t.Fatal("wrong passphrase decrypted the secret")
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	secret := make([]byte, 16)
+	if _, err := Split(secret[:15], nil, 0, 1, Group{Threshold: 1, Count: 1}); err == nil {
+		t.Fatal("odd secret length was accepted")
+	}
+	if _, err := Split(secret, nil, 0, 2, Group{Threshold: 1, Count: 1}); err == nil {
+		t.Fatal("group threshold above group count was accepted")
+	}
+	if _, err := Split(secret, nil, 0, 1, Group{Threshold: 1, Count: 3}); err == nil {
+		t.Fatal("member threshold of one with many members was accepted")
+	}
+}
+
+func TestShareBinary(t *testing.T) {
+	for _, vector := range testVectors {
+		for _, mnemonic := range vector.Mnemonics {
+			share, err := ParseMnemonic(mnemonic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := share.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := &Share{}
+			if err = decoded.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := decoded.Mnemonic()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if encoded != mnemonic {
+				t.Fatalf("mnemonic %q was decoded as %q", mnemonic, encoded)
+			}
+
+			b[len(b)/2] ^= 0x10
+			if err = decoded.UnmarshalBinary(b); err == nil {
+				t.Fatal("corrupt share was accepted")
+			}
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package kidwords
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Time-locked secrets start with a header made of the format version and the [TimeLock] parameters, followed by the seed of the first segment, the seeds of the remaining segments each masked by the end of the previous segment, and the secret encrypted by AES-128-GCM under the end of the last segment. The header is authenticated.
+const (
+	timeLockVersion     = 1
+	timeLockStateSize   = 16
+	maxTimeLockSegments = 64
+
+	// DefaultTimeLockMemoryCost is the memory in KiB used by every step of the time-lock.
+	DefaultTimeLockMemoryCost = 64 * 1024
+)
+
+// TimeLock is a puzzle that takes a known amount of sequential work to solve. Each segment is a chain of Argon2id passes, where every pass takes the output of the previous one, so the chain cannot be computed faster by adding processors, and memory hardness keeps specialized hardware from racing ahead. The segments have independent seeds, so they are created in parallel, but the seed of every segment is hidden by the end of the previous one, so they are solved one after another.
+type TimeLock struct {
+	// Iterations is the number of sequential Argon2id passes in each segment.
+	Iterations uint32
+	// MemoryCost is the memory in KiB used by each pass.
+	MemoryCost uint32
+	// Segments is the number of chains, which can be created in parallel.
+	Segments uint8
+}
+
+// Validate checks that the time-lock parameters are usable.
+func (t TimeLock) Validate() error {
+	if t.Iterations < 1 {
+		return errors.New("time-lock iterations must be at least 1")
+	}
+	if t.MemoryCost < 8 || t.MemoryCost > maxArgonMemory {
+		return fmt.Errorf("time-lock memory cost %d KiB is out of range [8-%d]", t.MemoryCost, maxArgonMemory)
+	}
+	if t.Segments < 1 || t.Segments > maxTimeLockSegments {
+		return fmt.Errorf("time-lock segments %d is out of range [1-%d]", t.Segments, maxTimeLockSegments)
+	}
+	return nil
+}
+
+// CalibrateTimeLock measures the speed of this machine and returns the [TimeLock] that takes about the given duration to solve on it. Faster machines solve it sooner, so calibrate on the machine that is expected to recover the secret. The segments match the number of processors, which shortens creation.
+func CalibrateTimeLock(d time.Duration, memoryCost uint32) (TimeLock, error) {
+	t := TimeLock{
+		Iterations: 1,
+		MemoryCost: memoryCost,
+		Segments:   uint8(min(max(runtime.NumCPU(), 1), maxTimeLockSegments)),
+	}
+	if err := t.Validate(); err != nil {
+		return t, err
+	}
+	if d <= 0 {
+		return t, errors.New("time-lock duration must be positive")
+	}
+
+	var (
+		state = make([]byte, timeLockStateSize)
+		salt  = []byte{timeLockVersion}
+		steps = 0
+		start = time.Now()
+	)
+	for steps < 3 || time.Since(start) < time.Second/4 {
+		state = t.step(state, salt)
+		steps++
+	}
+	perStep := time.Since(start) / time.Duration(steps)
+
+	total := uint64(d/perStep) + 1
+	perSegment := (total + uint64(t.Segments) - 1) / uint64(t.Segments)
+	if perSegment > 1<<32-1 {
+		return t, fmt.Errorf("time-lock duration %s is too long", d)
+	}
+	t.Iterations = uint32(perSegment)
+	return t, nil
+}
+
+func (t TimeLock) step(state, salt []byte) []byte {
+	return argon2.IDKey(state, salt, 1, t.MemoryCost, 1, timeLockStateSize)
+}
+
+// chain stops between steps once the context is done.
+func (t TimeLock) chain(ctx context.Context, seed, salt []byte, progress func()) ([]byte, error) {
+	state := seed
+	for i := uint32(0); i < t.Iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		state = t.step(state, salt)
+		if progress != nil {
+			progress()
+		}
+	}
+	return state, nil
+}
+
+func (t TimeLock) header() []byte {
+	header := []byte{timeLockVersion}
+	header = binary.AppendUvarint(header, uint64(t.Iterations))
+	header = binary.AppendUvarint(header, uint64(t.MemoryCost))
+	return append(header, t.Segments)
+}
+
+func segmentSalt(header []byte, segment int) []byte {
+	return append(append([]byte{}, header...), uint8(segment))
+}
+
+func newTimeLockCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// lock wraps the secret into the time-lock puzzle, computing all segments at once.
+func (t TimeLock) lock(ctx context.Context, secret []byte) ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	header := t.header()
+	seeds := make([]byte, int(t.Segments)*timeLockStateSize)
+	if _, err := rand.Read(seeds); err != nil {
+		return nil, err
+	}
+
+	ends := make([][]byte, t.Segments)
+	defer func() {
+		for _, end := range ends {
+			Secret(end).Wipe()
+		}
+	}()
+	errs := make([]error, t.Segments)
+	wg := sync.WaitGroup{}
+	for i := range ends {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			seed := seeds[i*timeLockStateSize : (i+1)*timeLockStateSize]
+			ends[i], errs[i] = t.chain(ctx, seed, segmentSalt(header, i), nil)
+		}(i)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	locked := append(header, seeds[:timeLockStateSize]...)
+	for i := 1; i < len(ends); i++ {
+		masked := make([]byte, timeLockStateSize)
+		for j := range masked {
+			masked[j] = seeds[i*timeLockStateSize+j] ^ ends[i-1][j]
+		}
+		locked = append(locked, masked...)
+	}
+	aead, err := newTimeLockCipher(ends[len(ends)-1])
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(locked, make([]byte, aead.NonceSize()), secret, header), nil
+}
+
+// unlock solves the time-lock puzzle, reporting progress after every step.
+func unlock(ctx context.Context, locked []byte, progress func(done, total uint64)) ([]byte, error) {
+	if len(locked) < 1 || locked[0] != timeLockVersion {
+		return nil, errors.New("time-lock version is not supported")
+	}
+	var (
+		t      TimeLock
+		cursor = 1
+	)
+	iterations, n := binary.Uvarint(locked[cursor:])
+	if n <= 0 || iterations > 1<<32-1 {
+		return nil, errors.New("time-lock iterations are corrupt")
+	}
+	cursor += n
+	memory, n := binary.Uvarint(locked[cursor:])
+	if n <= 0 || memory > maxArgonMemory {
+		return nil, errors.New("time-lock memory cost is corrupt")
+	}
+	cursor += n
+	if len(locked) < cursor+1 {
+		return nil, errors.New("time-lock is too short")
+	}
+	t.Iterations = uint32(iterations)
+	t.MemoryCost = uint32(memory)
+	t.Segments = locked[cursor]
+	cursor++
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	header := locked[:cursor]
+	if len(locked) < cursor+int(t.Segments)*timeLockStateSize {
+		return nil, errors.New("time-lock is too short")
+	}
+
+	var (
+		done  uint64
+		total = uint64(t.Iterations) * uint64(t.Segments)
+		step  func()
+	)
+	if progress != nil {
+		step = func() {
+			done++
+			progress(done, total)
+		}
+	}
+	state := locked[cursor : cursor+timeLockStateSize]
+	cursor += timeLockStateSize
+	for i := 0; i < int(t.Segments); i++ {
+		end, err := t.chain(ctx, state, segmentSalt(header, i), step)
+		if err != nil {
+			return nil, err
+		}
+		if i == int(t.Segments)-1 {
+			state = end
+			break
+		}
+		state = make([]byte, timeLockStateSize)
+		for j := range state {
+			state[j] = locked[cursor+j] ^ end[j]
+		}
+		cursor += timeLockStateSize
+	}
+
+	aead, err := newTimeLockCipher(state)
+	Secret(state).Wipe() // the state is the last step of the chain, not a part of the input
+	if err != nil {
+		return nil, err
+	}
+	secret, err := aead.Open(nil, make([]byte, aead.NonceSize()), locked[cursor:], header)
+	if err != nil {
+		return nil, errors.New("time-lock is corrupt")
+	}
+	return secret, nil
+}
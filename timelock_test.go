@@ -0,0 +1,122 @@
+package kidwords
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var cheapTimeLock = TimeLock{
+	Iterations: 4,
+	MemoryCost: 64,
+	Segments:   3,
+}
+
+func TestTimeLock(t *testing.T) {
+	secret := []byte("somethingElse")
+	locked, err := cheapTimeLock.lock(context.Background(), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var steps uint64
+	unlocked, err := unlock(context.Background(), locked, func(done, total uint64) {
+		if done != steps+1 || total != 12 {
+			t.Fatalf("unexpected progress %d of %d", done, total)
+		}
+		steps = done
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unlocked, secret) {
+		t.Fatalf("unlocked %q instead of %q", unlocked, secret)
+	}
+	if steps != 12 {
+		t.Fatalf("progress stopped at %d steps", steps)
+	}
+
+	for _, i := range []int{1, len(locked) - 30, len(locked) - 1} {
+		tampered := append([]byte{}, locked...)
+		tampered[i] ^= 1
+		if _, err = unlock(context.Background(), tampered, nil); err == nil {
+			t.Fatalf("tampered byte %d was not detected", i)
+		}
+	}
+}
+
+func TestCalibrateTimeLock(t *testing.T) {
+	lock, err := CalibrateTimeLock(time.Hour, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = lock.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if lock.Iterations < 2 {
+		t.Fatalf("an hour long time-lock takes only %d iterations", lock.Iterations)
+	}
+	if _, err = CalibrateTimeLock(0, 64); err == nil {
+		t.Fatal("zero duration was accepted")
+	}
+}
+
+func TestSplitWithTimeLock(t *testing.T) {
+	shards, err := Split("somethingElse", 5, 3, WithTimeLock(cheapTimeLock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := Combine(shards[1:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "somethingElse" {
+		t.Fatalf("recovered key %q does not match", key)
+	}
+
+	shards, err = Split("somethingElse", 5, 3,
+		WithTimeLock(cheapTimeLock),
+		WithPassphrase([]byte("open sesame")),
+		WithDecoy([]byte("open barley"), []byte("harmless")),
+		WithArgonParameters(ArgonParameters{
+			TimeCost:        1,
+			MemoryCost:      64,
+			ParallelThreads: 1,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for passphrase, expected := range map[string]string{
+		"open sesame": "somethingElse",
+		"open barley": "harmless",
+	} {
+		key, err := Combine(shards[:3], WithPassphrase([]byte(passphrase)), WithTimeLockProgress(func(done, total uint64) {}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != expected {
+			t.Fatalf("passphrase %q recovered %q instead of %q", passphrase, key, expected)
+		}
+	}
+}
+
+func TestTimeLockCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	shards, err := SplitContext(ctx, "somethingElse", 3, 2, WithTimeLock(cheapTimeLock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = CombineContext(ctx, shards[:2], WithTimeLockProgress(func(done, total uint64) {
+		cancel() // after the first step
+	}))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if _, err = SplitContext(ctx, "somethingElse", 3, 2, WithTimeLock(cheapTimeLock)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+}
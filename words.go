@@ -0,0 +1,74 @@
+package kidwords
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Words holds bytes that serialize to Kid Words of [StdEncoding]. Use it in application structs to store keys in JSON, YAML, configuration files, and databases as words. A nil value serializes to JSON null and SQL NULL.
+type Words []byte
+
+// String returns the words.
+func (w Words) String() string {
+	return StdEncoding.EncodeToString(w)
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (w Words) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (w *Words) UnmarshalText(text []byte) error {
+	b, err := StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*w = b
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (w Words) MarshalJSON() ([]byte, error) {
+	if w == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(w.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (w *Words) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*w = nil
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return w.UnmarshalText([]byte(s))
+}
+
+// Scan implements [database/sql.Scanner].
+func (w *Words) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*w = nil
+		return nil
+	case string:
+		return w.UnmarshalText([]byte(src))
+	case []byte:
+		return w.UnmarshalText(src)
+	default:
+		return fmt.Errorf("cannot scan %T into Kid Words", src)
+	}
+}
+
+// Value implements [database/sql/driver.Valuer].
+func (w Words) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return w.String(), nil
+}
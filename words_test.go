@@ -0,0 +1,73 @@
+package kidwords
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = Words{}
+	_ encoding.TextUnmarshaler = (*Words)(nil)
+	_ json.Marshaler           = Words{}
+	_ json.Unmarshaler         = (*Words)(nil)
+	_ sql.Scanner              = (*Words)(nil)
+	_ driver.Valuer            = Words{}
+)
+
+func TestWordsJSON(t *testing.T) {
+	type account struct {
+		Key      Words `json:"key"`
+		Recovery Words `json:"recovery"`
+	}
+	b, err := json.Marshal(account{Key: Words("marvel")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"key":"hole gold hush item half hint","recovery":null}`; string(b) != expected {
+		t.Fatalf("JSON %s does not match %s", b, expected)
+	}
+
+	decoded := account{}
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Key, []byte("marvel")) || decoded.Recovery != nil {
+		t.Fatalf("decoded account %+v does not match", decoded)
+	}
+	if err = json.Unmarshal([]byte(`{"key":"hole gold notaword"}`), &decoded); err == nil {
+		t.Fatal("unknown word was accepted")
+	}
+}
+
+func TestWordsSQL(t *testing.T) {
+	value, err := Words("marvel").Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "hole gold hush item half hint" {
+		t.Fatalf("value %v does not match", value)
+	}
+
+	var w Words
+	for _, src := range []any{value, []byte(value.(string))} {
+		if err = w.Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		if string(w) != "marvel" {
+			t.Fatalf("scanned %q", w)
+		}
+	}
+	if err = w.Scan(nil); err != nil || w != nil {
+		t.Fatal("NULL was not scanned")
+	}
+	if value, err = Words(nil).Value(); err != nil || value != nil {
+		t.Fatal("nil words are not NULL")
+	}
+	if err = w.Scan(42); err == nil {
+		t.Fatal("integer was scanned")
+	}
+}
@@ -1,6 +1,7 @@
 package kidwords
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
@@ -11,25 +12,43 @@ type Writer struct {
 	io.Writer
 	separator  SeparatorFunc
 	dictionary *dictionary.Dictionary
+	layout     *Layout
+	lines      int    // number of lines started
+	line       []byte // data of the current line
 }
 
 func NewWriter(out io.Writer, withOptions ...WriterOption) (*Writer, error) {
-	if out == nil {
-		out = io.Discard
+	o, err := newWriterOptions(withOptions)
+	if err != nil {
+		return nil, err
 	}
-	o := &writerOptions{}
+	if o.compress {
+		return nil, errors.New("compression requires a container, use NewContainerWriter")
+	}
+	if o.kindSet {
+		return nil, errors.New("payload kind requires a container, use NewContainerWriter")
+	}
+	return newWriter(out, o), nil
+}
 
-	var err error
+func newWriterOptions(withOptions []WriterOption) (*writerOptions, error) {
+	o := &writerOptions{}
 	for i, option := range withOptions {
-		if err = option.applyWriterOption(o); err != nil {
+		if err := option.applyWriterOption(o); err != nil {
 			return nil, fmt.Errorf("cannot apply option %d to Kids Words writer: %w", i+1, err)
 		}
 	}
+	return o, nil
+}
 
+func newWriter(out io.Writer, o *writerOptions) *Writer {
+	if out == nil {
+		out = io.Discard
+	}
 	if o.dictionary == nil {
 		o.dictionary = &dictionary.EnglishFourLetterNouns
 	}
-	if o.separator == nil {
+	if o.separator == nil && o.layout == nil {
 		o.separator = func() []byte {
 			return []byte(" ")
 		}
@@ -39,10 +58,14 @@ func NewWriter(out io.Writer, withOptions ...WriterOption) (*Writer, error) {
 		Writer:     out,
 		separator:  o.separator,
 		dictionary: o.dictionary,
-	}, nil
+		layout:     o.layout,
+	}
 }
 
 func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.layout != nil {
+		return w.writeLayout(p)
+	}
 	var (
 		j, l int
 		sep  []byte
@@ -72,6 +95,26 @@ func (w *Writer) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
+func (w *Writer) writeLayout(p []byte) (n int, err error) {
+	b := make([]byte, 0, len(p)*8)
+	for _, c := range p {
+		b = w.appendLayoutWord(b, c)
+	}
+	if _, err = w.Writer.Write(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close completes the last line of the [Layout]. It does not close the underlying [io.Writer].
+func (w *Writer) Close() error {
+	if w.layout == nil || len(w.line) == 0 {
+		return nil
+	}
+	_, err := w.Writer.Write(w.appendLineEnd(nil))
+	return err
+}
+
 // func NewWriter(w io.Writer) io.WriteCloser {
 // 	return ChecksumWriter(&Writer{
 // 		Writer: w,
@@ -88,20 +131,6 @@ func (w *Writer) Write(p []byte) (n int, err error) {
 // 	})
 // }
 
-// func WriteInt(w io.Writer, n int64) (err error) {
-// 	if n < 0 {
-// 		return errors.New("negative integers cannot be reliabily encoded to bytes")
-// 	}
-//
-// 	x := new(big.Int).SetInt64(n)
-// 	wrapped := NewWriter(w)
-// 	_, err = wrapped.Write(x.Bytes())
-// 	if err != nil {
-// 		return
-// 	}
-// 	return wrapped.Close()
-// }
-
 // func SeparatorHTML(perRow int) SeparatorFunc {
 // 	return func(wordCount int) []byte {
 // 		if wordCount%perRow == 0 {
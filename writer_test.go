@@ -27,3 +27,48 @@ func TestWriter(t *testing.T) {
 
 	test.GoldenMust(t, "test/testdata/writeRaw.golden", b.Bytes())
 }
+
+func TestWriterLayout(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Layout   Layout
+		Expected string
+	}{
+		{Name: "one line", Expected: "hole gold hush item half hint hook iron icon"},
+		{Name: "groups", Layout: Layout{GroupSize: 4}, Expected: "hole gold hush item  half hint hook iron  icon"},
+		{Name: "lines", Layout: Layout{GroupSize: 2, GroupsPerLine: 2}, Expected: "hole gold  hush item\nhalf hint  hook iron\nicon"},
+		{Name: "numbered", Layout: Layout{GroupSize: 3, GroupsPerLine: 1, Numbered: true}, Expected: "1. hole gold hush\n2. item half hint\n3. hook iron icon"},
+		{Name: "checksums", Layout: Layout{GroupSize: 3, GroupsPerLine: 2, Numbered: true, Checksum: true}, Expected: "1. hole gold hush  item half hint = flow\n2. hook iron icon = rose"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			w, err := NewWriter(b, WithLayout(c.Layout))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, chunk := range []string{"marv", "elous"} {
+				if _, err = w.Write([]byte(chunk)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != c.Expected {
+				t.Fatalf("layout %q does not match %q", b.String(), c.Expected)
+			}
+			decoded, err := ToString(b.String(), WithLayout(c.Layout))
+			if err != nil || decoded != "marvelous" {
+				t.Fatalf("layout decoded into %q with error %v", decoded, err)
+			}
+		})
+	}
+
+	if _, err := NewWriter(nil, WithLayout(Layout{GroupsPerLine: 2})); err == nil {
+		t.Fatal("groups per line were accepted without a group size")
+	}
+	if _, err := NewWriter(nil, WithLayout(Layout{}), WithSeparator(func() []byte { return nil })); err == nil {
+		t.Fatal("layout was combined with a separator function")
+	}
+}